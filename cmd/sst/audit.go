@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+var CmdAudit = &cli.Command{
+	Name: "audit",
+	Description: cli.Description{
+		Short: "Show who changed your app and when",
+		Long: strings.Join([]string{
+			"Shows the audit log of a stage of your app, newest first.",
+			"",
+			"```bash frame=\"none\"",
+			"sst audit --stage production",
+			"```",
+			"",
+			"Every command that changes a stage is recorded in your home once it's done. This",
+			"includes `deploy`, `remove`, `refresh`, `unlock`, `import`, the `state` commands that",
+			"edit the state, the `secret` commands that change secrets, and freezing a stage.",
+			"Each deploy that `sst dev` makes is recorded as `dev`.",
+			"",
+			"Each record has the command and its args, the user that ran it and their identity",
+			"in the home, the version of the CLI, the git commit and if there were uncommitted",
+			"changes, and how it went. The values of secrets are never recorded.",
+			"",
+			"You can narrow it down by the user, the command, and when it was run.",
+			"",
+			"```bash frame=\"none\"",
+			"sst audit --stage production --command deploy --since 24h",
+			"```",
+			"",
+			"To show the log of every stage of your app, use `--all-stages`.",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "all-stages",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Show the log of every stage",
+				Long:  "Show the audit log of every stage of your app instead of just the current one.",
+			},
+		},
+		{
+			Name: "user",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show the commands of a user",
+				Long:  "Only show the commands run by this user. Matches the user on the machine or their identity in the home.",
+			},
+		},
+		{
+			Name: "command",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show a command",
+				Long:  "Only show this command. Passing in `state` also shows `state edit`, `state remove`, and `state repair`.",
+			},
+		},
+		{
+			Name: "since",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show what was run after this",
+				Long:  "Only show the commands run after this. Takes a duration, like `24h`, a date, like `2026-01-31`, or a timestamp.",
+			},
+		},
+		{
+			Name: "until",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show what was run before this",
+				Long:  "Only show the commands run before this. Takes a duration, like `24h`, a date, like `2026-01-31`, or a timestamp.",
+			},
+		},
+		{
+			Name: "json",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Print it as JSON",
+				Long:  "Print the records as JSON.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst audit --all-stages --user jay --since 7d",
+			Description: cli.Description{
+				Short: "Show what a user has run in the last week",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		now := time.Now()
+		filter := provider.AuditFilter{
+			User:    c.String("user"),
+			Command: c.String("command"),
+		}
+		var err error
+		if filter.Since, err = parseAuditTime(c.String("since"), now); err != nil {
+			return err
+		}
+		if filter.Until, err = parseAuditTime(c.String("until"), now); err != nil {
+			return err
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		stage := p.App().Stage
+		if c.Bool("all-stages") {
+			stage = ""
+		}
+		records, err := provider.ListAudit(p.Backend(), p.App().Name, stage, filter.Since)
+		if err != nil {
+			return err
+		}
+		matched := []provider.AuditRecord{}
+		for _, record := range records {
+			if filter.Match(record) {
+				matched = append(matched, record)
+			}
+		}
+
+		if c.Bool("json") {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(matched)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		columns := []string{"STARTED", "COMMAND", "USER", "COMMIT", "RESULT", "DURATION"}
+		if c.Bool("all-stages") {
+			columns = append([]string{"STAGE"}, columns...)
+		}
+		fmt.Fprintln(writer, strings.Join(columns, "\t"))
+		for _, record := range matched {
+			commit := record.Commit
+			if len(commit) > 7 {
+				commit = commit[:7]
+			}
+			if record.Dirty {
+				commit += "*"
+			}
+			row := []string{
+				record.Started.Local().Format(time.DateTime),
+				strings.TrimSpace(record.Command + " " + strings.Join(record.Args, " ")),
+				record.User,
+				commit,
+				string(record.Result),
+				record.Duration.Round(time.Second).String(),
+			}
+			if c.Bool("all-stages") {
+				row = append([]string{record.Stage}, row...)
+			}
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	},
+}
+
+// parseAuditTime takes a duration before now, a date, or a timestamp.
+func parseAuditTime(input string, now time.Time) (time.Time, error) {
+	if input == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(input, "d"); ok {
+		if parsed, err := time.ParseDuration(days + "h"); err == nil {
+			return now.Add(-parsed * 24), nil
+		}
+	}
+	if parsed, err := time.ParseDuration(input); err == nil {
+		return now.Add(-parsed), nil
+	}
+	if parsed, err := time.ParseInLocation(time.DateOnly, input, time.Local); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, input); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, util.NewReadableError(nil, "Could not parse \""+input+"\", use a duration like 24h, a date like 2026-01-31, or a timestamp")
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+const maskedArg = "[secret]"
+
+// audit records the command once it's done. Commands that fail before the
+// project is loaded can't be recorded since there is no home to write to.
+// Failing to record is logged and doesn't fail the command.
+func (c *Cli) audit(active Command, started time.Time, err error) {
+	if c.project == nil {
+		return
+	}
+	names := []string{}
+	for _, cmd := range c.path[1:] {
+		names = append(names, cmd.Name)
+	}
+	record := &provider.AuditRecord{
+		Command:  strings.Join(names, " "),
+		Args:     c.auditArgs(active),
+		Version:  c.version,
+		Result:   provider.AuditResultSucceeded,
+		Started:  started.UTC(),
+		Duration: time.Since(started),
+	}
+	if err != nil {
+		record.Result = provider.AuditResultFailed
+		record.Error = err.Error()
+	}
+	if err := c.project.Audit(record); err != nil {
+		slog.Error("failed to write audit record", "err", err)
+	}
+}
+
+// auditArgs are the flags and arguments the command was run with, with the
+// sensitive ones masked.
+func (c *Cli) auditArgs(active Command) []string {
+	result := []string{}
+	sensitive := sensitiveFlags(&c.path[0], map[string]bool{})
+	flag.CommandLine.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		if sensitive[f.Name] {
+			value = maskedArg
+		}
+		result = append(result, "--"+f.Name+"="+value)
+	})
+	for i, arg := range c.arguments {
+		if i < len(active.Args) && active.Args[i].Sensitive {
+			arg = maskedArg
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// sensitiveFlags collects the flags marked sensitive by any command. Flags are
+// shared between commands, so one marked anywhere is masked everywhere.
+func sensitiveFlags(cmd *Command, result map[string]bool) map[string]bool {
+	for _, f := range cmd.Flags {
+		if f.Sensitive {
+			result[f.Name] = true
+		}
+	}
+	for _, child := range cmd.Children {
+		sensitiveFlags(child, result)
+	}
+	return result
+}
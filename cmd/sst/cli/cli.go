@@ -6,7 +6,9 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"golang.org/x/term"
@@ -26,6 +28,8 @@ type Cli struct {
 	Context   context.Context
 	cancel    context.CancelFunc
 	env       []string
+	branch    string
+	project   *project.Project
 }
 
 func New(ctx context.Context, cancel context.CancelFunc, root *Command, version string) (*Cli, error) {
@@ -86,9 +90,13 @@ func (c *Cli) Run() error {
 	}
 	if c.Bool("help") || active.Run == nil || len(c.arguments) < required {
 		return c.PrintHelp()
-	} else {
-		return active.Run(c)
 	}
+	started := time.Now()
+	err := active.Run(c)
+	if active.Audit {
+		c.audit(active, started, err)
+	}
+	return err
 }
 
 func (c *Cli) Cancel() {
@@ -128,6 +136,22 @@ func (c *Cli) Positional(index int) string {
 	return c.arguments[index]
 }
 
+// Forward rebuilds the command and flags the CLI was called with, without the
+// given flags, so it can be run again in a child process.
+func (c *Cli) Forward(skip ...string) []string {
+	result := []string{}
+	for _, cmd := range c.path[1:] {
+		result = append(result, cmd.Name)
+	}
+	flag.CommandLine.Visit(func(f *flag.Flag) {
+		if slices.Contains(skip, f.Name) {
+			return
+		}
+		result = append(result, "--"+f.Name+"="+f.Value.String())
+	})
+	return append(result, c.arguments...)
+}
+
 func (c *Cli) Env() []string {
 	return c.env
 }
@@ -141,6 +165,7 @@ type Command struct {
 	Examples    []Example            `json:"examples"`
 	Children    []*Command           `json:"children"`
 	Run         func(cli *Cli) error `json:"-"`
+	Audit       bool                 `json:"-"`
 }
 
 func (c *Command) init(parsed map[string]interface{}) {
@@ -182,6 +207,7 @@ type Argument struct {
 	Name        string      `json:"name"`
 	Required    bool        `json:"required"`
 	Description Description `json:"description"`
+	Sensitive   bool        `json:"-"`
 }
 
 type Description struct {
@@ -207,6 +233,7 @@ type Flag struct {
 	Name        string      `json:"name"`
 	Type        string      `json:"type"`
 	Description Description `json:"description"`
+	Sensitive   bool        `json:"-"`
 }
 
 type CommandPath []Command
@@ -306,6 +333,13 @@ func (c *Cli) Stage(cfgPath string) (string, error) {
 	stage := c.String("stage")
 	if stage == "" {
 		stage = os.Getenv("SST_STAGE")
+		if stage == "" && c.stageFromBranch() {
+			var err error
+			stage, err = c.branchStage(cfgPath)
+			if err != nil {
+				return "", err
+			}
+		}
 		if stage == "" {
 			stage = project.LoadPersonalStage(cfgPath)
 			if stage == "" {
@@ -48,6 +48,40 @@ func (c *Cli) Discover() (string, error) {
 	return match, nil
 }
 
+func (c *Cli) stageFromBranch() bool {
+	return c.Bool("stage-from-branch") || flag.SST_STAGE_FROM_BRANCH
+}
+
+// branchStage derives the stage from the git branch and saves it as the
+// personal stage, so commands run later without the flag use the same one.
+func (c *Cli) branchStage(cfgPath string) (string, error) {
+	branch, err := project.GitBranch(filepath.Dir(cfgPath))
+	if err != nil {
+		return "", util.NewReadableError(err, "Could not find the git branch to derive the stage from")
+	}
+	mappings := c.String("stage-map")
+	if mappings == "" {
+		mappings = flag.SST_STAGE_MAP
+	}
+	parsed, err := project.ParseStageMap(mappings)
+	if err != nil {
+		return "", util.NewReadableError(err, "Could not parse the stage map: "+err.Error())
+	}
+	stage := project.BranchStage(branch, parsed)
+	err = project.SetPersonalStage(cfgPath, stage)
+	if err != nil {
+		return "", err
+	}
+	slog.Info("derived stage from branch", "branch", branch, "stage", stage)
+	c.branch = branch
+	return stage, nil
+}
+
+// Branch is the git branch the stage was derived from, if it was.
+func (c *Cli) Branch() string {
+	return c.branch
+}
+
 func (c *Cli) InitProject() (*project.Project, error) {
 	slog.Info("initializing project", "version", c.version)
 
@@ -61,42 +95,24 @@ func (c *Cli) InitProject() (*project.Project, error) {
 		return nil, util.NewReadableError(err, "Could not find stage")
 	}
 
+	if c.Bool("no-config-cache") {
+		flag.SST_NO_CONFIG_CACHE = true
+	}
+
 	p, err := project.New(&project.ProjectConfig{
 		Version: c.version,
 		Stage:   stage,
 		Config:  cfgPath,
+		Branch:  c.branch,
 	})
 	if err != nil {
 		return nil, err
 	}
 	godotenv.Load(filepath.Join(p.PathRoot(), ".env"))
-
-	if flag.SST_LOG == "" {
-		_, err = logFile.Seek(0, 0)
-		if err != nil {
-			return nil, err
-		}
-		sstLog := p.PathLog("sst")
-		logPath := p.PathLog("")
-		os.MkdirAll(logPath, 0755)
-		nextLogFile, err := os.Create(sstLog)
-		if err != nil {
-			return nil, util.NewReadableError(err, "Could not create log file")
-		}
-		_, err = io.Copy(nextLogFile, logFile)
-		if err != nil {
-			return nil, util.NewReadableError(err, "Could not copy log file")
-		}
-		logFile.Close()
-		defer func() {
-			err = os.RemoveAll(filepath.Join(os.TempDir(), logFile.Name()))
-			if err != nil {
-				slog.Error("failed to remove temp log file", "err", err)
-			}
-		}()
-		logFile = nextLogFile
+	err = c.moveLog(p)
+	if err != nil {
+		return nil, err
 	}
-	c.configureLog()
 
 	spin := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
 	defer spin.Stop()
@@ -124,11 +140,82 @@ func (c *Cli) InitProject() (*project.Project, error) {
 
 	app := p.App()
 	slog.Info("loaded config", "app", app.Name, "stage", app.Stage)
+	c.project = p
 
 	c.configureLog()
 	return p, nil
 }
 
+// InitProjectCached is InitProject for commands that only read from the home.
+// It uses the last config that was evaluated for the stage, or the app and
+// home passed in, so it doesn't need to build and run the config.
+func (c *Cli) InitProjectCached() (*project.Project, error) {
+	slog.Info("initializing cached project", "version", c.version)
+
+	cfgPath, err := c.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	stage, err := c.Stage(cfgPath)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not find stage")
+	}
+
+	p, err := project.NewCached(&project.ProjectConfig{
+		Version: c.version,
+		Stage:   stage,
+		Config:  cfgPath,
+		Branch:  c.branch,
+	}, c.String("app"), c.String("home"))
+	if err != nil {
+		return nil, err
+	}
+	godotenv.Load(filepath.Join(p.PathRoot(), ".env"))
+	err = c.moveLog(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.LoadHome(); err != nil {
+		return nil, err
+	}
+	slog.Info("loaded cached config", "app", p.App().Name, "stage", p.App().Stage)
+	c.project = p
+	return p, nil
+}
+
+// moveLog moves the log into the project once it's known.
+func (c *Cli) moveLog(p *project.Project) error {
+	if flag.SST_LOG == "" {
+		_, err := logFile.Seek(0, 0)
+		if err != nil {
+			return err
+		}
+		sstLog := p.PathLog("sst")
+		logPath := p.PathLog("")
+		os.MkdirAll(logPath, 0755)
+		nextLogFile, err := os.Create(sstLog)
+		if err != nil {
+			return util.NewReadableError(err, "Could not create log file")
+		}
+		_, err = io.Copy(nextLogFile, logFile)
+		if err != nil {
+			return util.NewReadableError(err, "Could not copy log file")
+		}
+		logFile.Close()
+		defer func() {
+			err = os.RemoveAll(filepath.Join(os.TempDir(), logFile.Name()))
+			if err != nil {
+				slog.Error("failed to remove temp log file", "err", err)
+			}
+		}()
+		logFile = nextLogFile
+	}
+	c.configureLog()
+	return nil
+}
+
 func (c *Cli) configureLog() {
 	writers := []io.Writer{logFile}
 	if c.Bool("print-logs") || flag.SST_PRINT_LOGS {
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/cmd/sst/mosaic/ui"
+	"github.com/sst/sst/v3/pkg/freeze"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+type configProvider struct {
+	Version string                 `json:"version,omitempty"`
+	Package string                 `json:"package,omitempty"`
+	Args    map[string]interface{} `json:"args"`
+}
+
+type configOutput struct {
+	Name       string                       `json:"name"`
+	Stage      string                       `json:"stage"`
+	Home       string                       `json:"home"`
+	Removal    string                       `json:"removal"`
+	Protect    bool                         `json:"protect"`
+	Protected  []string                     `json:"protected"`
+	Freeze     []freeze.Window              `json:"freeze"`
+	Watch      []string                     `json:"watch"`
+	References map[string]project.Reference `json:"references"`
+	Providers  map[string]configProvider    `json:"providers"`
+	Backend    map[string]string            `json:"backend"`
+	// BackendError is why the details of the home couldn't be loaded
+	BackendError string `json:"backendError,omitempty"`
+}
+
+var CmdConfig = &cli.Command{
+	Name: "config",
+	Description: cli.Description{
+		Short: "Inspect the config of your app",
+	},
+	Children: []*cli.Command{
+		{
+			Name: "print",
+			Description: cli.Description{
+				Short: "Print the evaluated config",
+				Long: strings.Join([]string{
+					"Prints the config of your app as it was evaluated for the stage.",
+					"",
+					"```bash frame=\"none\"",
+					"sst config print --stage production",
+					"```",
+					"",
+					"This is what the `app` function in your `sst.config.ts` returned, along with the",
+					"versions of the providers that are installed and the details of your home, like the",
+					"account and the bucket the state is stored in.",
+					"",
+					"This is useful when your config depends on the stage, to check what it resolved to",
+					"before running something like `sst remove`.",
+					"",
+					"Provider args that look sensitive, like tokens or secret keys, are shown as `[secret]`.",
+					"",
+					"You can print it as JSON with `--json`.",
+					"",
+					"```bash frame=\"none\"",
+					"sst config print --stage production --json",
+					"```",
+					"",
+					"If the details of your home can't be loaded, the JSON has the reason in `backendError`.",
+				}, "\n"),
+			},
+			Flags: []cli.Flag{
+				{
+					Name: "json",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Print it as JSON",
+						Long:  "Print the config as JSON.",
+					},
+				},
+			},
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+
+				app := p.App()
+				output := configOutput{
+					Name:       app.Name,
+					Stage:      app.Stage,
+					Home:       app.Home,
+					Removal:    app.Removal,
+					Protect:    app.Protect,
+					Protected:  app.Protected,
+					Freeze:     app.Freeze,
+					Watch:      app.Watch,
+					References: app.References,
+					Providers:  map[string]configProvider{},
+					Backend:    map[string]string{},
+				}
+				for name, value := range app.Providers {
+					args, _ := value.(map[string]interface{})
+					entry := configProvider{
+						Args: maskArgs(args),
+					}
+					if version, ok := args["version"].(string); ok {
+						entry.Version = version
+					}
+					delete(entry.Args, "version")
+					for _, lock := range p.ProviderLock() {
+						if lock.Name == name {
+							entry.Version = lock.Version
+							entry.Package = lock.Package
+						}
+					}
+					output.Providers[name] = entry
+				}
+				info, err := provider.Info(p.Backend())
+				if err != nil {
+					output.BackendError = err.Error()
+				}
+				for _, line := range info {
+					output.Backend[line.Key] = line.Value
+				}
+
+				if c.Bool("json") {
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "  ")
+					return encoder.Encode(output)
+				}
+				if output.BackendError != "" {
+					ui.Error("Failed to load home information: " + output.BackendError)
+				}
+
+				renderKeyValue("App", output.Name)
+				renderKeyValue("Stage", output.Stage)
+				renderKeyValue("Home", output.Home)
+				renderKeyValue("Removal", output.Removal)
+				renderKeyValue("Protect", fmt.Sprint(output.Protect))
+				renderList("Protected", output.Protected)
+				windows := []string{}
+				for _, window := range output.Freeze {
+					windows = append(windows, window.String())
+				}
+				renderList("Freeze", windows)
+				renderList("Watch", output.Watch)
+				references := []string{}
+				for name, ref := range output.References {
+					references = append(references, name+" "+ui.TEXT_DIM.Render(ref.App+"/"+ref.Stage))
+				}
+				sort.Strings(references)
+				renderList("References", references)
+
+				names := []string{}
+				for name := range output.Providers {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				fmt.Println()
+				for _, name := range names {
+					entry := output.Providers[name]
+					renderKeyValue(name, entry.Version)
+					keys := []string{}
+					for key := range entry.Args {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						fmt.Println(indent("") + ui.TEXT_DIM.Render(key+": ") + formatOutput(entry.Args[key]))
+					}
+				}
+
+				if len(info) > 0 {
+					fmt.Println()
+					for _, line := range info {
+						renderKeyValue(line.Key, line.Value)
+					}
+				}
+				return nil
+			},
+		},
+	},
+}
+
+func renderList(key string, values []string) {
+	if len(values) == 0 {
+		renderKeyValue(key, "-")
+		return
+	}
+	renderKeyValue(key, values[0])
+	for _, value := range values[1:] {
+		fmt.Println(indent("") + ui.TEXT_INFO.Render(value))
+	}
+}
+
+var sensitiveArg = regexp.MustCompile(`(?i)(secret|token|password|passphrase|credential|private|api_?key|access_?key|external_?id)`)
+
+// maskArgs copies the provider args with anything that looks sensitive masked
+func maskArgs(args map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range args {
+		if sensitiveArg.MatchString(key) {
+			result[key] = project.MaskedValue
+			continue
+		}
+		result[key] = maskValue(value)
+	}
+	return result
+}
+
+func maskValue(value interface{}) interface{} {
+	switch cast := value.(type) {
+	case map[string]interface{}:
+		return maskArgs(cast)
+	case []interface{}:
+		result := []interface{}{}
+		for _, item := range cast {
+			result = append(result, maskValue(item))
+		}
+		return result
+	default:
+		return value
+	}
+}
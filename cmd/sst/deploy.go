@@ -1,10 +1,14 @@
 package main
 
 import (
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sst/sst/v3/cmd/sst/cli"
 	"github.com/sst/sst/v3/cmd/sst/mosaic/ui"
+	"github.com/sst/sst/v3/internal/util"
 	"github.com/sst/sst/v3/pkg/bus"
 	"github.com/sst/sst/v3/pkg/project"
 	"github.com/sst/sst/v3/pkg/server"
@@ -29,6 +33,26 @@ var CmdDeploy = &cli.Command{
 			"sst deploy --target MyComponent",
 			"```",
 			"",
+			"Or skip a component, along with everything that depends on it, with `--exclude`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --exclude MyComponent",
+			"```",
+			"",
+			"The changes are previewed first, so components that are new to your config are still",
+			"created, unless they are inside the excluded component.",
+			"",
+			"To force a component to be replaced instead of updated, use `--replace`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --replace MyComponent",
+			"```",
+			"",
+			"Replacing a component replaces all the resources in it.",
+			"",
+			"These take the same names as `--target` and can be comma separated. The resolved",
+			"resources are printed before the deploy starts.",
+			"",
 			"All the resources are deployed as concurrently as possible, based on their dependencies.",
 			"For resources like your container images, sites, and functions; it first builds them and then deploys the generated assets.",
 			"",
@@ -47,6 +71,13 @@ var CmdDeploy = &cli.Command{
 			"",
 			"So only one site is built at a time, 4 functions are built at a time, and only 1 container is built at a time.",
 			"",
+			"If a provider is rate limiting its API calls, you can also limit how many resources are",
+			"deployed at the same time.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --parallel 4",
+			"```",
+			"",
 			"You can set the above environment variables to change this when you run `sst deploy`. This is useful for CI",
 			"environments where you want to control this based on how much memory your CI machine has.",
 			"",
@@ -69,7 +100,22 @@ var CmdDeploy = &cli.Command{
 			"",
 			"This is useful when deploying a new stage with a lot of resources. You want",
 			"to be able to deploy as many resources as possible and then come back and",
-			"fix the errors.",
+			"fix the errors. Resources that depend on a failed resource are skipped, and the",
+			"summary at the end lists what succeeded, what failed, and what was skipped. To",
+			"know what was skipped, the changes are previewed before the deploy starts.",
+			"",
+			"If nothing changed since the last successful deploy of the stage, the deploy is",
+			"skipped. This is checked by hashing your config and the files it imports, the",
+			"provider lock, the provider environment and any `SST_` environment variables,",
+			"your secrets, and the CLI version. Editing the state with the `state` commands, or",
+			"unlocking a deploy that didn't finish, makes the next deploy run.",
+			"",
+			"This doesn't pick up changes made to your resources outside of SST, or other",
+			"environment variables your config reads. In that case you can `--force` the deploy.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --force",
+			"```",
 			"",
 			"The `sst dev` command deploys your resources a little differently. It skips",
 			"deploying resources that are going to be run locally. Sometimes you want to",
@@ -79,6 +125,89 @@ var CmdDeploy = &cli.Command{
 			"sst deploy --dev",
 			"```",
 			"The `--dev` flag will deploy your resources as if you were running `sst dev`.",
+			"",
+			"If there's a `sst.policy.json` next to your `sst.config.ts`, the changes are previewed",
+			"and checked against its rules before anything is deployed. Violations of `mandatory`",
+			"rules stop the deploy, while `advisory` ones are only reported.",
+			"",
+			"```json title=\"sst.policy.json\"",
+			"{",
+			"  \"rules\": [",
+			"    {",
+			"      \"id\": \"no-db-replace\",",
+			"      \"description\": \"Never replace a Postgres cluster in production\",",
+			"      \"severity\": \"mandatory\",",
+			"      \"stages\": [\"production\"],",
+			"      \"types\": [\"aws:rds/cluster:Cluster\"],",
+			"      \"ops\": [\"replace\", \"delete\"]",
+			"    },",
+			"    {",
+			"      \"id\": \"no-public-buckets\",",
+			"      \"types\": [\"aws:s3/*\"],",
+			"      \"inputs\": [{ \"path\": \"acl\", \"in\": [\"public-read\", \"public-read-write\"] }]",
+			"    }",
+			"  ]",
+			"}",
+			"```",
+			"",
+			"To deploy exactly what was reviewed, pass in a plan saved with [`sst diff --save-plan`](#diff).",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage production --plan plan.json",
+			"```",
+			"",
+			"The changes are previewed again before anything is deployed. If your config or state has",
+			"changed, or the changes don't match the plan, the deploy is stopped.",
+			"",
+			"You can have a stage expire with `--ttl`. This is useful for stages that are created for",
+			"a pull request. Expired stages are removed when you run `sst stage gc`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage pr-123 --ttl 72h",
+			"```",
+			"",
+			"Every deploy with a `--ttl` that succeeds moves the expiry. Deploy with `--ttl 0` to stop the stage from",
+			"expiring.",
+			"",
+			"In a monorepo with more than one app, you can deploy all of them with `--all`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage production --all",
+			"```",
+			"",
+			"This finds every `sst.config.ts` in the current directory. Or you can list the apps and",
+			"the ones they depend on in a `sst.workspace.json`.",
+			"",
+			"```json title=\"sst.workspace.json\"",
+			"{",
+			"  \"apps\": [",
+			"    { \"path\": \"apps/shared\" },",
+			"    { \"path\": \"apps/api\", \"dependsOn\": [\"apps/shared\"] }",
+			"  ],",
+			"  \"concurrency\": 4",
+			"}",
+			"```",
+			"",
+			"Apps are deployed after the ones they depend on, and the rest are deployed at the same",
+			"time, up to the `--concurrency`. Each app is deployed with its own lock and state. If an",
+			"app fails, the apps that depend on it are skipped.",
+			"",
+			"If your app has `protected` components, the deploy is also stopped when they would be",
+			"deleted or replaced. You can allow it for a component with `--allow-destroy`. The changes",
+			"are previewed first and the deploy is held to that preview, so it fails instead of",
+			"deleting a component the preview didn't show. `sst dev` takes `--allow-destroy` as well.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage production --allow-destroy Database",
+			"```",
+			"",
+			"Deploys are also stopped during the freeze windows of the stage. These are set with",
+			"`freeze` in your `sst.config.ts` or with [`sst stage freeze`](#stage-freeze). If a deploy",
+			"can't wait, you can override the freeze with a reason. The reason is recorded with the update.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage production --override-freeze \"Fix checkout outage\"",
+			"```",
 		}, "\n"),
 	},
 	Flags: []cli.Flag{
@@ -89,6 +218,55 @@ var CmdDeploy = &cli.Command{
 				Long:  "Only run it for the given component.",
 			},
 		},
+		{
+			Name: "force",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Deploy even if nothing changed",
+				Long:  "Deploy even if nothing changed since the last deploy.",
+			},
+		},
+		{
+			Name: "exclude",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Skip a component and its dependents",
+				Long:  "Skip the given component and everything that depends on it.",
+			},
+		},
+		{
+			Name: "replace",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Force a component to be replaced",
+				Long:  "Replace the given component, and all the resources in it, instead of updating it.",
+			},
+		},
+		{
+			Name: "parallel",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Limit the number of concurrent operations",
+				Long:  "Limit the number of resources that are deployed concurrently.",
+			},
+		},
+		{
+			Name: "allow-destroy",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Allow a protected component to be destroyed",
+				Long:  "Allow the given protected component to be deleted or replaced.",
+			},
+		},
+		overrideFreezeFlag,
+		{
+			Name: "ttl",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Expire the stage after a duration",
+				Long:  "Expire the stage after the given duration, like `72h`. Expired stages are removed by `sst stage gc`.",
+			},
+		},
 		{
 			Name: "continue",
 			Type: "bool",
@@ -97,6 +275,14 @@ var CmdDeploy = &cli.Command{
 				Long:  "Continue on error and try to deploy as many resources as possible.",
 			},
 		},
+		{
+			Name: "plan",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only deploy the changes in a saved plan",
+				Long:  "Only deploy the changes in a plan saved with `sst diff --save-plan`.",
+			},
+		},
 		{
 			Name: "dev",
 			Type: "bool",
@@ -105,6 +291,8 @@ var CmdDeploy = &cli.Command{
 				Long:  "Deploy resources like `sst dev` would.",
 			},
 		},
+		allFlag,
+		concurrencyFlag,
 	},
 	Examples: []cli.Example{
 		{
@@ -114,7 +302,11 @@ var CmdDeploy = &cli.Command{
 			},
 		},
 	},
+	Audit: true,
 	Run: func(c *cli.Cli) error {
+		if c.Bool("all") {
+			return runWorkspace(c)
+		}
 		p, err := c.InitProject()
 		if err != nil {
 			return err
@@ -126,6 +318,43 @@ var CmdDeploy = &cli.Command{
 			target = strings.Split(c.String("target"), ",")
 		}
 
+		exclude := []string{}
+		if c.String("exclude") != "" {
+			exclude = strings.Split(c.String("exclude"), ",")
+		}
+		replace := []string{}
+		if c.String("replace") != "" {
+			replace = strings.Split(c.String("replace"), ",")
+		}
+		allowDestroy := []string{}
+		if c.String("allow-destroy") != "" {
+			allowDestroy = strings.Split(c.String("allow-destroy"), ",")
+		}
+		parallel := 0
+		if c.String("parallel") != "" {
+			parallel, err = strconv.Atoi(c.String("parallel"))
+			if err != nil || parallel < 1 {
+				return util.NewReadableError(err, "The --parallel flag must be a positive number")
+			}
+		}
+
+		var ttl *time.Duration
+		if c.String("ttl") != "" {
+			parsed, err := time.ParseDuration(c.String("ttl"))
+			if err != nil || parsed < 0 {
+				return util.NewReadableError(err, "The --ttl flag must be a duration like 72h")
+			}
+			ttl = &parsed
+		}
+
+		plan := ""
+		if c.String("plan") != "" {
+			plan, err = filepath.Abs(c.String("plan"))
+			if err != nil {
+				return err
+			}
+		}
+
 		var wg errgroup.Group
 		defer wg.Wait()
 		out := make(chan interface{})
@@ -150,12 +379,20 @@ var CmdDeploy = &cli.Command{
 		defer ui.Destroy()
 		defer c.Cancel()
 		err = p.Run(c.Context, &project.StackInput{
-			Command:    "deploy",
-			Target:     target,
-			Dev:        c.Bool("dev"),
-			ServerPort: s.Port,
-			Verbose:    c.Bool("verbose"),
-			Continue:   c.Bool("continue"),
+			Command:        "deploy",
+			Target:         target,
+			Exclude:        exclude,
+			Replace:        replace,
+			Parallel:       parallel,
+			Dev:            c.Bool("dev"),
+			ServerPort:     s.Port,
+			Verbose:        c.Bool("verbose"),
+			Continue:       c.Bool("continue"),
+			Force:          c.Bool("force"),
+			AllowDestroy:   allowDestroy,
+			TTL:            ttl,
+			Plan:           plan,
+			OverrideFreeze: c.String("override-freeze"),
 		})
 		if err != nil {
 			return err
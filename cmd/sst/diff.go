@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -48,6 +49,19 @@ var CmdDiff = &cli.Command{
 			"```",
 			"",
 			"This is useful because in dev mode, you app is deployed a little differently.",
+			"",
+			"The changes are also checked against the rules in your `sst.policy.json`, if you have one.",
+			"Any violations are listed and the command exits with an error if a `mandatory` rule is violated.",
+			"",
+			"If the changes need to be approved before they are deployed, save them to a plan file.",
+			"",
+			"```bash frame=\"none\"",
+			"sst diff --stage production --save-plan plan.json",
+			"```",
+			"",
+			"The plan includes the changes and a hash of your config and state. Once it's been reviewed,",
+			"pass it to [`sst deploy`](#deploy) and it'll refuse to deploy anything that's not in the plan.",
+			"The plan is not saved if the changes violate a `mandatory` rule.",
 		}, "\n"),
 	},
 	Flags: []cli.Flag{
@@ -58,6 +72,16 @@ var CmdDiff = &cli.Command{
 				Long:  "Only run it for the given component.",
 			},
 		},
+		{
+			Name: "save-plan",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Save the changes to a plan file",
+				Long: strings.Join([]string{
+					"Save the changes to a plan file that can be applied with `sst deploy --plan`.",
+				}, "\n"),
+			},
+		},
 		{
 			Name: "dev",
 			Type: "bool",
@@ -68,6 +92,8 @@ var CmdDiff = &cli.Command{
 				}, "\n"),
 			},
 		},
+		allFlag,
+		concurrencyFlag,
 	},
 	Examples: []cli.Example{
 		{
@@ -78,6 +104,9 @@ var CmdDiff = &cli.Command{
 		},
 	},
 	Run: func(c *cli.Cli) error {
+		if c.Bool("all") {
+			return runWorkspace(c)
+		}
 		p, err := c.InitProject()
 		if err != nil {
 			return err
@@ -89,6 +118,14 @@ var CmdDiff = &cli.Command{
 			target = strings.Split(c.String("target"), ",")
 		}
 
+		savePlan := ""
+		if c.String("save-plan") != "" {
+			savePlan, err = filepath.Abs(c.String("save-plan"))
+			if err != nil {
+				return err
+			}
+		}
+
 		var wg errgroup.Group
 		defer wg.Wait()
 		outputs := []*apitype.ResOutputsEvent{}
@@ -122,17 +159,22 @@ var CmdDiff = &cli.Command{
 			Dev:        c.Bool("dev"),
 			Target:     target,
 			Verbose:    c.Bool("verbose"),
+			SavePlan:   savePlan,
 		})
-		if err != nil {
+		// still print the changes when they violate a policy
+		if err != nil && err != project.ErrPolicyViolation {
 			return err
 		}
+		if err == nil && savePlan != "" {
+			ui.Success("Saved plan to " + savePlan)
+		}
 		if len(outputs) == 0 {
 			fmt.Println(
 				ui.TEXT_HIGHLIGHT_BOLD.Render("➜"),
 				ui.TEXT_NORMAL_BOLD.Render(" No changes"),
 			)
 			fmt.Println()
-			return nil
+			return err
 		}
 		for _, output := range outputs {
 			icon := ""
@@ -210,6 +252,6 @@ var CmdDiff = &cli.Command{
 			}
 			fmt.Println()
 		}
-		return nil
+		return err
 	},
 }
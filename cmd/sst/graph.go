@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/graph"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+var CmdGraph = &cli.Command{
+	Name: "graph",
+	Description: cli.Description{
+		Short: "Print the dependency graph of your app",
+		Long: strings.Join([]string{
+			"Prints the graph of the resources in your app and how they depend on each other.",
+			"",
+			"The graph is read from the state of the stage, so it's the one from the last",
+			"deploy. It includes the parent of each resource and the resources it depends on.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --stage production | dot -Tsvg > graph.svg",
+			"```",
+			"",
+			"By default it prints a [DOT](https://graphviz.org/doc/info/lang.html) graph. You can",
+			"also print it as a `mermaid` diagram or as `json` with `--format`.",
+			"",
+			"The graph of a large app can be hard to read. You can only show what's inside a",
+			"component with `--root`, and only certain types with `--type`. Types can be comma",
+			"separated and use `*` as a wildcard.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --root MyApi --type \"aws:lambda*\"",
+			"```",
+			"",
+			"Or `--collapse` the resources into the components they belong to, to only see how",
+			"your components depend on each other.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --collapse --format mermaid",
+			"```",
+			"",
+			"To see which functions and services are linked to which resources instead, use",
+			"`--links`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst graph --links",
+			"```",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The format to print in",
+				Long:  "The format to print the graph in. One of `dot`, `mermaid`, or `json`. Defaults to `dot`.",
+			},
+		},
+		{
+			Name: "root",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show a component",
+				Long:  "Only show the given component and the resources inside it.",
+			},
+		},
+		{
+			Name: "type",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Only show some types",
+				Long:  "Only show resources of the given types, like `sst:aws:Function` or `aws:s3*`.",
+			},
+		},
+		{
+			Name: "collapse",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Collapse resources into their components",
+				Long:  "Collapse the resources into the top level components they belong to.",
+			},
+		},
+		{
+			Name: "links",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Show links instead of dependencies",
+				Long:  "Show which functions and services are linked to which resources.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst graph --collapse --format mermaid",
+			Description: cli.Description{
+				Short: "Print how the components depend on each other",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		format := c.String("format")
+		if format == "" {
+			format = "dot"
+		}
+		if format != "dot" && format != "mermaid" && format != "json" {
+			return util.NewReadableError(nil, "Unknown format \""+format+"\", use dot, mermaid, or json")
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return project.ErrStageNotFound
+			}
+			return err
+		}
+
+		var result *graph.Graph
+		if c.Bool("links") {
+			targets := []string{}
+			for name := range complete.Links {
+				targets = append(targets, name)
+			}
+			result = graph.Links(complete.Resources, targets)
+		} else {
+			result = graph.New(complete.Resources)
+			if root := c.String("root"); root != "" {
+				result = result.Root(root)
+				if len(result.Nodes) == 0 {
+					return util.NewReadableError(nil, "Component not found: "+root)
+				}
+			}
+			if c.Bool("collapse") {
+				result = result.Collapse()
+			}
+		}
+		if types := c.String("type"); types != "" {
+			result = result.Types(strings.Split(types, ","))
+		}
+
+		switch format {
+		case "dot":
+			fmt.Println(result.DOT(p.App().Name + "/" + p.App().Stage))
+		case "mermaid":
+			fmt.Println(result.Mermaid())
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+		return nil
+	},
+}
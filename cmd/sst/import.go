@@ -0,0 +1,112 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/cmd/sst/mosaic/ui"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/bus"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/server"
+	"golang.org/x/sync/errgroup"
+)
+
+var CmdImport = &cli.Command{
+	Name: "import",
+	Description: cli.Description{
+		Short: "Import existing resources into your app",
+		Long: strings.Join([]string{
+			"Imports a list of existing resources into your app in a single deploy.",
+			"",
+			"```bash frame=\"none\"",
+			"sst import --manifest resources.json --stage production",
+			"```",
+			"",
+			"The manifest uses the same format as a Pulumi import file. Each resource has the",
+			"name and type it has in your app, and the ID of the live resource.",
+			"",
+			"```json title=\"resources.json\"",
+			"{",
+			"  \"resources\": [",
+			"    {",
+			"      \"name\": \"MyBucketBucket\",",
+			"      \"type\": \"aws:s3/bucketV2:BucketV2\",",
+			"      \"id\": \"my-existing-bucket\"",
+			"    }",
+			"  ]",
+			"}",
+			"```",
+			"",
+			"You can find the name and type of a resource with `sst resources`, or in the",
+			"output of `sst diff`.",
+			"",
+			"Once the deploy is done, it reports how each resource went. The ones that were",
+			"imported come with the snippet to add to your `sst.config.ts`, so they keep being",
+			"imported until you remove it. The ones that don't match the live resource come with",
+			"the args that need to change, like a regular failed import. The ones that were already",
+			"in the state before the deploy are not imported again and are reported as already managed.",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "manifest",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The resources to import",
+				Long:  "Path to the JSON file with the resources to import.",
+			},
+		},
+		overrideFreezeFlag,
+	},
+	Audit: true,
+	Run: func(c *cli.Cli) error {
+		if c.String("manifest") == "" {
+			return util.NewReadableError(nil, "Pass in the resources to import with --manifest")
+		}
+		path, err := filepath.Abs(c.String("manifest"))
+		if err != nil {
+			return err
+		}
+		imports, err := project.LoadImportManifest(path)
+		if err != nil {
+			return err
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		var wg errgroup.Group
+		defer wg.Wait()
+		ui := ui.New(c.Context)
+		s, err := server.New()
+		if err != nil {
+			return err
+		}
+		wg.Go(func() error {
+			defer c.Cancel()
+			return s.Start(c.Context, p)
+		})
+		events := bus.SubscribeAll()
+		defer close(events)
+		wg.Go(func() error {
+			for evt := range events {
+				ui.Event(evt)
+			}
+			return nil
+		})
+		defer ui.Destroy()
+		defer c.Cancel()
+		return p.Run(c.Context, &project.StackInput{
+			Command:        "deploy",
+			Imports:        imports,
+			ServerPort:     s.Port,
+			Verbose:        c.Bool("verbose"),
+			OverrideFreeze: c.String("override-freeze"),
+		})
+	},
+}
@@ -238,9 +238,47 @@ var root = &cli.Command{
 					"2. Store this in the `.sst/stage` file and reads from it in the future.",
 					"",
 					"This stored stage is called your **personal stage**.",
+					"",
+					"In CI, you can derive the stage from the git branch with `--stage-from-branch`.",
+				}, "\n"),
+			},
+		},
+		{
+			Name: "stage-from-branch",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Derive the stage from the git branch",
+				Long: strings.Join([]string{
+					"Derive the stage from the git branch that is checked out, if the stage is not passed in.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --stage-from-branch",
+					"```",
+					"",
+					"The branch is lowercased and anything that isn't a letter or a number is turned into a dash. So `feature/Login_Page` becomes `feature-login-page`. Stages longer than 24 characters are cut short and end with a hash of the branch, so they stay unique.",
+					"",
+					"If the HEAD is detached, like it is in most CI providers, the branch is read from variables like `GITHUB_HEAD_REF` or `CI_COMMIT_REF_NAME`.",
+					"",
+					"Use `--stage-map` to map some branches to a stage of your choosing.",
+					"",
+					"```bash frame=\"none\"",
+					"sst deploy --stage-from-branch --stage-map main=production,develop=staging",
+					"```",
+					"",
+					"The derived stage is shown when the command starts and saved as your personal stage.",
+					"",
+					"You can also use the `SST_STAGE_FROM_BRANCH` and `SST_STAGE_MAP` environment variables.",
 				}, "\n"),
 			},
 		},
+		{
+			Name: "stage-map",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Map git branches to stages",
+				Long:  "Map git branches to stages with `--stage-from-branch`, like `main=production,develop=staging`.",
+			},
+		},
 		{
 			Name: "verbose",
 			Type: "bool",
@@ -281,6 +319,31 @@ var root = &cli.Command{
 				}, "\n"),
 			},
 		},
+		{
+			Name: "no-config-cache",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "Evaluate the config without the cache",
+				Long: strings.Join([]string{
+					"",
+					"Evaluate your `sst.config.ts` without using the cache.",
+					"",
+					"The `app` function in your config is evaluated before every command. What it returns is",
+					"cached in the `.sst/` directory for each stage, until your config, any of the files it",
+					"imports, or your environment variables change.",
+					"",
+					"```bash",
+					"sst [command] --no-config-cache",
+					"```",
+					"It can also be set using the `SST_NO_CONFIG_CACHE` environment variable.",
+					"",
+					"```bash",
+					"SST_NO_CONFIG_CACHE=1 sst [command]",
+					"```",
+					"",
+				}, "\n"),
+			},
+		},
 		{
 			Name: "config",
 			Type: "string",
@@ -458,6 +521,15 @@ var root = &cli.Command{
 						Long:  "Defaults to using `multi` mode. Use `mono` to get a single stream of all child process logs or `basic` to not spawn any child processes.",
 					},
 				},
+				overrideFreezeFlag,
+				{
+					Name: "allow-destroy",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Allow a protected component to be destroyed",
+						Long:  "Allow the given protected component to be deleted or replaced by the deploys.",
+					},
+				},
 			},
 			Args: []cli.Argument{
 				{
@@ -491,6 +563,13 @@ var root = &cli.Command{
 		},
 		CmdDeploy,
 		CmdDiff,
+		CmdOutputs,
+		CmdGraph,
+		CmdResources,
+		CmdImport,
+		CmdAudit,
+		CmdStage,
+		CmdConfig,
 		{
 			Name: "add",
 			Description: cli.Description{
@@ -805,6 +884,20 @@ var root = &cli.Command{
 					"```bash frame=\"none\"",
 					"sst remove --target MyComponent",
 					"```",
+					"",
+					"Components in the `protected` list of your `sst.config.ts` are not removed unless you allow it with `--allow-destroy`.",
+					"",
+					"```bash frame=\"none\"",
+					"sst remove --stage staging --allow-destroy Database",
+					"```",
+					"",
+					"To see what would be removed without removing anything, pass in `--dry-run`. This lists the resources that would be deleted, the ones that would be retained because of the `removal` setting, and the ones that are protected. It also tells you if the stage itself is protected.",
+					"",
+					"```bash frame=\"none\"",
+					"sst remove --stage production --dry-run",
+					"```",
+					"",
+					"After a remove, the resources that were retained are listed along with their physical IDs in `.sst/retained.json`, so you can clean them up later.",
 				}, "\n"),
 			},
 			Flags: []cli.Flag{
@@ -816,8 +909,28 @@ var root = &cli.Command{
 						Long:  "Only run it for the given component.",
 					},
 				},
+				{
+					Name: "allow-destroy",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Allow a protected component to be destroyed",
+						Long:  "Allow the given protected component to be removed.",
+					},
+				},
+				{
+					Name: "dry-run",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Show what would be removed",
+						Long:  "Show what would be removed without removing anything.",
+					},
+				},
+				overrideFreezeFlag,
+				allFlag,
+				concurrencyFlag,
 			},
-			Run: CmdRemove,
+			Audit: true,
+			Run:   CmdRemove,
 		},
 		{
 			Name: "unlock",
@@ -831,6 +944,7 @@ var root = &cli.Command{
 					"This should not usually happen, but it can prevent you from deploying. You can run `sst unlock` to release the lock.",
 				}, "\n"),
 			},
+			Audit: true,
 			Run: func(c *cli.Cli) error {
 				p, err := c.InitProject()
 				if err != nil {
@@ -938,8 +1052,31 @@ var root = &cli.Command{
 		{
 			Name:   "common-errors",
 			Hidden: true,
+			Flags: []cli.Flag{
+				{
+					Name: "project",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Include the rules of the project",
+						Long:  "Include the rules from the `sst.errors.json` of the project, before the built in ones.",
+					},
+				},
+			},
 			Run: func(cli *cli.Cli) error {
-				data, err := json.MarshalIndent(project.CommonErrors, "", "  ")
+				// only the built in rules by default, the docs are generated
+				// from them
+				rules := project.CommonErrors
+				if cli.Bool("project") {
+					cfgPath, err := cli.Discover()
+					if err != nil {
+						return err
+					}
+					rules, err = project.LoadCommonErrors(filepath.Dir(cfgPath))
+					if err != nil {
+						return err
+					}
+				}
+				data, err := json.MarshalIndent(rules, "", "  ")
 				if err != nil {
 					return err
 				}
@@ -980,8 +1117,12 @@ var root = &cli.Command{
 						Long:  "Only run it for the given component.",
 					},
 				},
+				overrideFreezeFlag,
+				allFlag,
+				concurrencyFlag,
 			},
-			Run: CmdRefresh,
+			Audit: true,
+			Run:   CmdRefresh,
 		},
 		CmdState,
 		CmdCert,
@@ -222,7 +222,11 @@ func CmdMosaic(c *cli.Cli) error {
 
 	wg.Go(func() error {
 		defer c.Cancel()
-		return deployer.Start(c.Context, p, server)
+		allowDestroy := []string{}
+		if c.String("allow-destroy") != "" {
+			allowDestroy = strings.Split(c.String("allow-destroy"), ",")
+		}
+		return deployer.Start(c.Context, p, server, c.String("override-freeze"), allowDestroy)
 	})
 
 	currentExecutable, _ := os.Executable()
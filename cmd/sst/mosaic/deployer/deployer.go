@@ -18,13 +18,16 @@ type DeployFailedEvent struct {
 	Error string
 }
 
-func Start(ctx context.Context, p *project.Project, server *server.Server) error {
+// Start deploys the app every time a watched file changes. Dev deploys are
+// stopped by a freeze window and by protected components like any other, the
+// override and the allowed components are passed to each.
+func Start(ctx context.Context, p *project.Project, server *server.Server, overrideFreeze string, allowDestroy []string) error {
 	log := slog.Default().With("service", "deployer")
 	log.Info("starting")
 	defer log.Info("done")
 	watchedFiles := make(map[string]bool)
-	events := bus.Subscribe(ctx, &watcher.FileChangedEvent{}, &DeployRequestedEvent{}, &project.BuildSuccessEvent{})
-	lastBuildHash := ""
+	events := bus.Subscribe(ctx, &watcher.FileChangedEvent{}, &DeployRequestedEvent{}, &project.BuildSuccessEvent{}, &project.CompleteEvent{})
+	lastHash := ""
 	for {
 		log.Info("waiting for trigger")
 		select {
@@ -33,20 +36,31 @@ func Start(ctx context.Context, p *project.Project, server *server.Server) error
 		case evt := <-events:
 			switch evt := evt.(type) {
 			case *project.BuildSuccessEvent:
-				lastBuildHash = evt.Hash
-				log.Info("build hash", "hash", lastBuildHash)
 				for _, file := range evt.Files {
 					watchedFiles[file] = true
 				}
 				continue
+			case *project.CompleteEvent:
+				if evt.Old {
+					continue
+				}
+				// only skip redeploying inputs that deployed successfully
+				lastHash = ""
+				if evt.Finished && len(evt.Errors) == 0 {
+					lastHash = evt.InputHash
+				}
+				log.Info("input hash", "hash", lastHash)
+				continue
 			case *watcher.FileChangedEvent, *DeployRequestedEvent:
 				if evt, ok := evt.(*watcher.FileChangedEvent); !ok || watchedFiles[evt.Path] {
 					log.Info("deploying")
 					err := p.Run(ctx, &project.StackInput{
-						Command:    "deploy",
-						Dev:        true,
-						ServerPort: server.Port,
-						SkipHash:   lastBuildHash,
+						Command:        "deploy",
+						Dev:            true,
+						ServerPort:     server.Port,
+						SkipHash:       lastHash,
+						OverrideFreeze: overrideFreeze,
+						AllowDestroy:   allowDestroy,
 					})
 					if err != nil {
 						log.Error("stack deploy error", "error", err)
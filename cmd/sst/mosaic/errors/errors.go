@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sst/sst/v3/cmd/sst/mosaic/aws"
 	"github.com/sst/sst/v3/cmd/sst/mosaic/aws/appsync"
@@ -23,15 +24,18 @@ var transformers = []ErrorTransformer{
 	exact(project.ErrAppNameChanged, "The app name has changed.\n\nIf you want to rename the app, make sure to run `sst remove` to remove the old app first. Alternatively, remove the \".sst\" folder and try again.\n"),
 	exact(project.ErrV2Config, "You are using sst v3 and this looks like an sst v2 config"),
 	exact(project.ErrStageNotFound, "Stage not found"),
+	exact(project.ErrConfigNotCached, "The config hasn't been run for this stage yet. Pass in the name and home of your app with `--app` and `--home`."),
 	exact(project.ErrPassphraseInvalid, "The passphrase for this app / stage is missing or invalid"),
 	exact(aws.ErrIoTDelay, "This aws account has not had iot initialized in it before which sst depends on. It may take a few minutes before it is ready."),
 	exact(project.ErrStackRunFailed, ""),
 	exact(provider.ErrLockExists, ""),
+	exact(provider.ErrLockHeld, "Another sst process on this machine is updating this app / stage. Wait for it to finish, or stop it, and try again."),
 	exact(project.ErrVersionInvalid, "The version range defined in the config is invalid"),
 	exact(provider.ErrCloudflareMissingAccount, "The Cloudflare Account ID was not able to be determined from this token. Make sure it has permissions to fetch account information or you can set the CLOUDFLARE_DEFAULT_ACCOUNT_ID environment variable to the account id you want to use."),
 	exact(server.ErrServerNotFound, "Could not find an `sst dev` session to connect to. Since you are running a command outside of the multiplexer be sure to start `sst dev` first."),
 	exact(provider.ErrBucketMissing, "The state bucket is missing, it may have been accidentally deleted. Go to https://console.aws.amazon.com/systems-manager/parameters/%252Fsst%252Fbootstrap/description?tab=Table and check if the state bucket mentioned there exists. If it doesn't you can recreate it or delete the `/sst/bootstrap` key to force recreation."),
 	exact(project.ErrProtectedStage, "Cannot remove protected stage. To remove a protected stage edit your sst.config.ts and remove the `protect` property."),
+	exact(project.ErrPolicyViolation, "The changes violate a mandatory policy in sst.policy.json. Fix the violations listed above and try again."),
 	exact(provider.ErrLockNotFound, "This app / stage is not locked"),
 	exact(aws.ErrAppsyncNotReady, "SST creates an appsync event api to power live lambda. After 10 seconds of waiting this cli could not connect to it."),
 	exact(js.ErrTopLevelImport, "Your sst.config.ts has top level imports - this is not allowed. Move imports inside the function they are used and do a dynamic import: `const mod = await import(\"./mod\")`"),
@@ -49,6 +53,19 @@ var transformers = []ErrorTransformer{
 	match(func(err *project.ErrProviderVersionTooLow) string {
 		return fmt.Sprintf("You specified version %s of the \"%s\" provider. SST needs %s or higher.", err.Version, err.Name, err.Needed)
 	}),
+	match(func(err *project.ErrPlanChanged) string {
+		return "The changes no longer match the saved plan. Run `sst diff --save-plan` again and review the new plan.\n   - " + strings.Join(err.Reasons, "\n   - ")
+	}),
+	match(func(err *project.ErrProtectedResource) string {
+		return "Protected components would be deleted or replaced: " + strings.Join(err.Names, ", ") + ". If this is intended, run again with `--allow-destroy " + strings.Join(err.Names, ",") + "`."
+	}),
+	match(func(err *project.ErrFrozen) string {
+		result := "The " + err.Stage + " stage is frozen until " + err.Until.Local().Format(time.RFC1123)
+		if err.Window.Reason != "" {
+			result += ": " + err.Window.Reason
+		}
+		return result + ". If this can't wait, run again with `--override-freeze \"<reason>\"`."
+	}),
 	match(func(err *project.ErrVersionMismatch) string {
 		return fmt.Sprintf("You are using v%s which does not match v%s in your \"sst.config.ts\".", err.Needed, err.Received)
 	}),
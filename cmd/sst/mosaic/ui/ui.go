@@ -22,6 +22,7 @@ import (
 	"github.com/sst/sst/v3/cmd/sst/mosaic/ui/common"
 	"github.com/sst/sst/v3/pkg/flag"
 	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/policy"
 
 	"golang.org/x/crypto/ssh/terminal"
 )
@@ -220,7 +221,7 @@ func (u *UI) Event(unknown interface{}) {
 
 	case *project.StackCommandEvent:
 		u.reset()
-		u.header(evt.Version, evt.App, evt.Stage)
+		u.header(evt.Version, evt.App, evt.Stage, evt.Branch)
 		u.blank()
 		if evt.Command == "deploy" {
 			u.mode = ProgressModeDeploy
@@ -265,6 +266,146 @@ func (u *UI) Event(unknown interface{}) {
 		u.reset()
 		break
 
+	case *project.TimingsEvent:
+		u.blank()
+		for i, timing := range evt.Resources {
+			if i == 10 {
+				break
+			}
+			message := fmt.Sprintf("%-8s", timing.Duration.Round(100*time.Millisecond)) + " " + u.FormatURN(timing.URN)
+			if timing.Critical {
+				message += TEXT_DIM.Render(" (critical path)")
+			}
+			u.printEvent(TEXT_INFO, "Slowest", message)
+		}
+		if len(evt.CriticalPath) > 0 {
+			critical := time.Duration(0)
+			for _, timing := range evt.Resources {
+				if timing.Critical {
+					critical += timing.Duration
+				}
+			}
+			u.printEvent(TEXT_INFO, "Critical", fmt.Sprintf("%v across %d resources, see .sst/timings.json", critical.Round(100*time.Millisecond), len(evt.CriticalPath)))
+		}
+
+	case *project.FreezeEvent:
+		message := "until " + evt.Until.Local().Format(time.RFC1123)
+		if evt.Window.Reason != "" {
+			message += " " + TEXT_DIM.Render("("+evt.Window.Reason+")")
+		}
+		u.printEvent(TEXT_WARNING, "Frozen", message, "   "+TEXT_WARNING.Render("overridden")+" "+evt.Override)
+		u.blank()
+
+	case *project.TargetsEvent:
+		for _, urn := range evt.Target {
+			u.printEvent(TEXT_INFO, "Target", u.FormatURN(urn))
+		}
+		for _, urn := range evt.Exclude {
+			u.printEvent(TEXT_DIM, "Exclude", u.FormatURN(urn))
+		}
+		for _, urn := range evt.Replace {
+			u.printEvent(TEXT_WARNING, "Replace", u.FormatURN(urn))
+		}
+		u.blank()
+
+	case *project.ProtectedEvent:
+		for _, change := range evt.Changes {
+			color := TEXT_DANGER
+			status := "blocked"
+			if change.Allowed {
+				color = TEXT_WARNING
+				status = "allowed"
+			}
+			u.printEvent(
+				color,
+				"Protected",
+				u.FormatURN(change.URN)+" "+TEXT_DIM.Render("("+change.Op+")"),
+				"   "+color.Render(status)+" "+change.Name+" is protected",
+			)
+		}
+		u.blank()
+
+	case *project.RemovePreviewEvent:
+		for _, res := range evt.Deleted {
+			u.printEvent(TEXT_DANGER, "Delete", u.FormatURN(res.URN)+" "+TEXT_DIM.Render(res.ID))
+		}
+		for _, res := range evt.Retained {
+			u.printEvent(TEXT_WARNING, "Retain", u.FormatURN(res.URN)+" "+TEXT_DIM.Render(res.ID))
+		}
+		for _, change := range evt.Protected {
+			color := TEXT_DANGER
+			status := "blocked"
+			if change.Allowed {
+				color = TEXT_WARNING
+				status = "allowed"
+			}
+			u.printEvent(color, "Protected", u.FormatURN(change.URN), "   "+color.Render(status)+" "+change.Name+" is protected")
+		}
+		u.blank()
+		u.printEvent(TEXT_INFO, "Dry run", fmt.Sprintf("%d to delete, %d to retain, %d protected", len(evt.Deleted), len(evt.Retained), len(evt.Protected)))
+		if evt.StageProtected {
+			u.printEvent(TEXT_DANGER, "Protected", "The stage is protected, remove the `protect` property from your sst.config.ts to remove it")
+		}
+		u.blank()
+
+	case *project.RetainedEvent:
+		u.blank()
+		for _, res := range evt.Resources {
+			u.printEvent(TEXT_WARNING, "Retained", u.FormatURN(res.URN)+" "+TEXT_DIM.Render(res.ID))
+		}
+		u.printEvent(TEXT_INFO, "Retained", fmt.Sprintf("%d resources were left behind, see .sst/retained.json", len(evt.Resources)))
+
+	case *project.ImportReportEvent:
+		for _, result := range evt.Results {
+			color := TEXT_DANGER
+			switch result.Status {
+			case project.ImportStatusImported:
+				color = TEXT_SUCCESS
+			case project.ImportStatusMismatch, project.ImportStatusManaged:
+				color = TEXT_WARNING
+			}
+			message := result.Name + " " + TEXT_DIM.Render(result.Type+" "+result.ID)
+			if result.URN != "" {
+				message = u.FormatURN(result.URN) + " " + TEXT_DIM.Render(result.ID)
+			}
+			detail := color.Render(string(result.Status))
+			if result.Message != "" {
+				detail += " " + TEXT_DIM.Render(result.Message)
+			}
+			u.printEvent(color, "Import", message, "   "+detail)
+		}
+		imported := []project.ImportResult{}
+		for _, result := range evt.Results {
+			if result.Status == project.ImportStatusImported {
+				imported = append(imported, result)
+			}
+		}
+		if len(imported) > 0 {
+			u.blank()
+			u.println(TEXT_NORMAL.Render("Add the following to your config so these stay imported:"))
+			for _, result := range imported {
+				u.blank()
+				u.println(TEXT_DIM.Render("   // " + result.URN))
+				u.println(TEXT_INFO.Render("   " + result.Snippet))
+			}
+		}
+		u.blank()
+
+	case *project.PolicyEvent:
+		for _, violation := range evt.Violations {
+			color := TEXT_WARNING
+			if violation.Severity == policy.SeverityMandatory {
+				color = TEXT_DANGER
+			}
+			u.printEvent(
+				color,
+				"Policy",
+				u.FormatURN(violation.URN)+" "+TEXT_DIM.Render("("+violation.Op+")"),
+				"   "+color.Render(string(violation.Severity))+" "+violation.Rule+": "+violation.Message,
+			)
+		}
+		u.blank()
+
 	case *apitype.ResourcePreEvent:
 		u.timing[evt.Metadata.URN] = time.Now()
 		if slices.Contains(IGNORED_RESOURCES, evt.Metadata.Type) {
@@ -451,6 +592,18 @@ func (u *UI) Event(unknown interface{}) {
 				TEXT_DANGER_BOLD.Render(IconX),
 				TEXT_NORMAL_BOLD.Render("  Failed    "),
 			)
+			if evt.Summary != nil {
+				u.println(
+					TEXT_DIM_BOLD.Render("   "),
+					TEXT_NORMAL.Render(fmt.Sprintf("%d succeeded, %d failed, %d skipped", len(evt.Summary.Succeeded), len(evt.Summary.Failed), len(evt.Summary.Skipped))),
+				)
+				for _, urn := range evt.Summary.Skipped {
+					u.println(
+						TEXT_DIM_BOLD.Render("   Skipped: "),
+						TEXT_DIM.Render(u.FormatURN(urn)),
+					)
+				}
+			}
 
 			u.blank()
 			for _, status := range evt.Errors {
@@ -469,32 +622,27 @@ func (u *UI) Event(unknown interface{}) {
 
 				importDiffs, ok := evt.ImportDiffs[status.URN]
 				if ok {
-					isSSTComponent := strings.Contains(status.URN, "::sst")
-					if isSSTComponent {
-						u.println(TEXT_NORMAL.Render("\n\nSet the following in your transform:"))
+					fix := project.NewImportFix(status.URN, importDiffs)
+					if fix.Transform {
+						u.println(TEXT_NORMAL.Render("\n\nSet the following in your transform so the import matches:"))
 					}
-					if !isSSTComponent {
-						u.println(TEXT_NORMAL.Render("\n\nSet the following:"))
+					if !fix.Transform {
+						u.println(TEXT_NORMAL.Render("\n\nSet the following in the args so the import matches:"))
 					}
-					for _, diff := range importDiffs {
-						value, _ := json.Marshal(diff.Old)
-						if diff.Old == nil {
-							value = []byte("undefined")
-						}
-						u.print(TEXT_NORMAL.Render("   - "))
-						if isSSTComponent {
-							u.print(TEXT_INFO.Render("`args." + string(diff.Input) + " = " + string(value) + ";`"))
-						}
-						if !isSSTComponent {
-							u.print(TEXT_INFO.Render("`" + string(diff.Input) + ": " + string(value) + ",`"))
-						}
-						u.blank()
+					u.blank()
+					for _, line := range strings.Split(fix.Snippet, "\n") {
+						u.println(TEXT_INFO.Render("   " + line))
 					}
+					u.blank()
 				} else {
 					u.blank()
 				}
 			}
 
+			if len(evt.ImportDiffs) > 0 {
+				u.println(TEXT_DIM.Render("The import fixes are also in .sst/import-fixes.json"))
+			}
+
 			if evt.UpdateID != "" {
 				u.blank()
 				u.println(
@@ -606,7 +754,7 @@ func (u *UI) Destroy() {
 	}
 }
 
-func (u *UI) header(version, app, stage string) {
+func (u *UI) header(version, app, stage, branch string) {
 	if u.hasHeader {
 		return
 	}
@@ -623,9 +771,13 @@ func (u *UI) header(version, app, stage string) {
 		TEXT_NORMAL_BOLD.Render(fmt.Sprintf("%-12s", "App:")),
 		TEXT_DIM.Render(app),
 	)
+	label := stage
+	if branch != "" {
+		label += " (from branch " + branch + ")"
+	}
 	u.println(
 		TEXT_NORMAL_BOLD.Render(fmt.Sprintf("   %-12s", "Stage:")),
-		TEXT_DIM.Render(stage),
+		TEXT_DIM.Render(label),
 	)
 
 	if u.options.Dev {
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/provider"
+	"github.com/yalp/jsonpath"
+)
+
+var CmdOutputs = &cli.Command{
+	Name: "outputs",
+	Description: cli.Description{
+		Short: "Print the outputs of your app",
+		Long: strings.Join([]string{
+			"Prints the outputs returned by the `run` function of your `sst.config.ts`.",
+			"",
+			"The outputs are read from the state of the stage, so they are the ones from the",
+			"last deploy. It doesn't build your app or make any changes.",
+			"",
+			"Your `sst.config.ts` isn't run either. The name and home of your app are taken from",
+			"the last time any other command ran it for the stage. If it never has, like in a",
+			"fresh CI checkout, pass them in with `--app` and `--home`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst outputs --stage production --app my-app --home aws",
+			"```",
+			"",
+			"```bash frame=\"none\"",
+			"sst outputs --stage production",
+			"```",
+			"",
+			"You can select a single value with `--query`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst outputs --stage production --query api.url",
+			"```",
+			"",
+			"And print them in a different format with `--format`. This can be `json`, `dotenv`,",
+			"`shell`, or `table`. Nested values are flattened with an `_`.",
+			"",
+			"```bash frame=\"none\"",
+			"eval \"$(sst outputs --stage production --format shell)\"",
+			"```",
+			"",
+			"This is useful in CI when a job needs something from a stage that it isn't deploying.",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "app",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The name of your app",
+				Long:  "The name of your app. Only needed if the config hasn't been run for the stage before.",
+			},
+		},
+		{
+			Name: "home",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The home of your app",
+				Long:  "The home of your app, like `aws`, `cloudflare`, or `local`. Only needed if the config hasn't been run for the stage before.",
+			},
+		},
+		{
+			Name: "query",
+			Type: "string",
+			Description: cli.Description{
+				Short: "Select a value from the outputs",
+				Long:  "Select a value from the outputs with a JSONPath like `api.url` or `$.sites[0]`.",
+			},
+		},
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The format to print in",
+				Long:  "The format to print the outputs in. One of `json`, `dotenv`, `shell`, or `table`. Defaults to `json`.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst outputs --stage production --query api.url",
+			Description: cli.Description{
+				Short: "Print the API URL in production",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		format := c.String("format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "dotenv" && format != "shell" && format != "table" {
+			return util.NewReadableError(nil, "Unknown format \""+format+"\", use json, dotenv, shell, or table")
+		}
+
+		p, err := c.InitProjectCached()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		complete, err := p.GetCompleted(c.Context)
+		if err != nil {
+			if errors.Is(err, provider.ErrStateNotFound) {
+				return project.ErrStageNotFound
+			}
+			return err
+		}
+
+		var value interface{} = complete.Outputs
+		if query := c.String("query"); query != "" {
+			if !strings.HasPrefix(query, "$") {
+				query = "$." + query
+			}
+			value, err = jsonpath.Read(value, query)
+			if err != nil {
+				return util.NewReadableError(err, "Could not find \""+c.String("query")+"\" in the outputs")
+			}
+		}
+
+		if format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(value)
+		}
+
+		if _, ok := value.(map[string]interface{}); !ok {
+			if _, ok := value.([]interface{}); !ok {
+				fmt.Println(formatOutput(value))
+				return nil
+			}
+		}
+		flat := map[string]string{}
+		flattenOutputs("", value, flat)
+		keys := []string{}
+		for key := range flat {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		switch format {
+		case "dotenv":
+			for _, key := range keys {
+				fmt.Println(key + "=" + quoteDotenv(flat[key]))
+			}
+		case "shell":
+			for _, key := range keys {
+				fmt.Println("export " + key + "=" + quoteShell(flat[key]))
+			}
+		case "table":
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			for _, key := range keys {
+				fmt.Fprintln(writer, key+"\t"+flat[key])
+			}
+			writer.Flush()
+		}
+		return nil
+	},
+}
+
+var invalidOutputKey = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func flattenOutputs(prefix string, value interface{}, result map[string]string) {
+	join := func(key string) string {
+		key = invalidOutputKey.ReplaceAllString(key, "_")
+		if prefix == "" {
+			return key
+		}
+		return prefix + "_" + key
+	}
+	switch cast := value.(type) {
+	case map[string]interface{}:
+		for key, item := range cast {
+			flattenOutputs(join(key), item, result)
+		}
+	case []interface{}:
+		for index, item := range cast {
+			flattenOutputs(join(fmt.Sprint(index)), item, result)
+		}
+	default:
+		result[prefix] = formatOutput(value)
+	}
+}
+
+func formatOutput(value interface{}) string {
+	switch cast := value.(type) {
+	case string:
+		return cast
+	case nil:
+		return ""
+	default:
+		data, _ := json.Marshal(cast)
+		return string(data)
+	}
+}
+
+func quoteDotenv(value string) string {
+	if value == "" || strings.ContainsAny(value, " #\"'\n\t=$") {
+		return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value) + "\""
+	}
+	return value
+}
+
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -12,6 +12,10 @@ import (
 )
 
 func CmdRefresh(c *cli.Cli) error {
+	if c.Bool("all") {
+		return runWorkspace(c)
+	}
+
 	p, err := c.InitProject()
 	if err != nil {
 		return err
@@ -45,10 +49,11 @@ func CmdRefresh(c *cli.Cli) error {
 	defer ui.Destroy()
 	defer c.Cancel()
 	err = p.Run(c.Context, &project.StackInput{
-		Command:    "refresh",
-		Target:     target,
-		ServerPort: s.Port,
-		Verbose:    c.Bool("verbose"),
+		Command:        "refresh",
+		Target:         target,
+		OverrideFreeze: c.String("override-freeze"),
+		ServerPort:     s.Port,
+		Verbose:        c.Bool("verbose"),
 	})
 	if err != nil {
 		return err
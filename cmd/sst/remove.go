@@ -12,6 +12,10 @@ import (
 )
 
 func CmdRemove(c *cli.Cli) error {
+	if c.Bool("all") {
+		return runWorkspace(c)
+	}
+
 	p, err := c.InitProject()
 	if err != nil {
 		return err
@@ -23,6 +27,11 @@ func CmdRemove(c *cli.Cli) error {
 		target = strings.Split(c.String("target"), ",")
 	}
 
+	allowDestroy := []string{}
+	if c.String("allow-destroy") != "" {
+		allowDestroy = strings.Split(c.String("allow-destroy"), ",")
+	}
+
 	var wg errgroup.Group
 	defer wg.Wait()
 	ui := ui.New(c.Context)
@@ -45,10 +54,13 @@ func CmdRemove(c *cli.Cli) error {
 	defer ui.Destroy()
 	defer c.Cancel()
 	err = p.Run(c.Context, &project.StackInput{
-		Command:    "remove",
-		Target:     target,
-		ServerPort: s.Port,
-		Verbose:    c.Bool("verbose"),
+		Command:        "remove",
+		Target:         target,
+		AllowDestroy:   allowDestroy,
+		DryRun:         c.Bool("dry-run"),
+		OverrideFreeze: c.String("override-freeze"),
+		ServerPort:     s.Port,
+		Verbose:        c.Bool("verbose"),
 	})
 	if err != nil {
 		return err
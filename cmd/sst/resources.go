@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+var CmdResources = &cli.Command{
+	Name: "resources",
+	Description: cli.Description{
+		Short: "List the resources in your app",
+		Long: strings.Join([]string{
+			"Lists every resource that SST manages for a stage of your app.",
+			"",
+			"```bash frame=\"none\"",
+			"sst resources --stage production",
+			"```",
+			"",
+			"The list is read from the state of the stage, so it's the one from the last deploy.",
+			"For each resource it prints its type, provider, physical ID, region, account, the",
+			"component it belongs to, and when it was created and last modified.",
+			"",
+			"It's printed as a table by default. You can also print it as `csv` or `json` with",
+			"`--format`. These also include the full URN of each resource.",
+			"",
+			"```bash frame=\"none\"",
+			"sst resources --format csv > resources.csv",
+			"```",
+			"",
+			"To list the resources of every stage of your app, use `--all-stages`.",
+			"",
+			"```bash frame=\"none\"",
+			"sst resources --all-stages --format json",
+			"```",
+			"",
+			"The state is not decrypted, so any values that are secret are shown as `[secret]`.",
+		}, "\n"),
+	},
+	Flags: []cli.Flag{
+		{
+			Name: "format",
+			Type: "string",
+			Description: cli.Description{
+				Short: "The format to print in",
+				Long:  "The format to print the resources in. One of `table`, `csv`, or `json`. Defaults to `table`.",
+			},
+		},
+		{
+			Name: "all-stages",
+			Type: "bool",
+			Description: cli.Description{
+				Short: "List the resources of every stage",
+				Long:  "List the resources of every stage of your app instead of just the current one.",
+			},
+		},
+	},
+	Examples: []cli.Example{
+		{
+			Content: "sst resources --all-stages --format csv",
+			Description: cli.Description{
+				Short: "Export the resources of every stage as CSV",
+			},
+		},
+	},
+	Run: func(c *cli.Cli) error {
+		format := c.String("format")
+		if format == "" {
+			format = "table"
+		}
+		if format != "table" && format != "csv" && format != "json" {
+			return util.NewReadableError(nil, "Unknown format \""+format+"\", use table, csv, or json")
+		}
+
+		p, err := c.InitProject()
+		if err != nil {
+			return err
+		}
+		defer p.Cleanup()
+
+		stages := []string{p.App().Stage}
+		if c.Bool("all-stages") {
+			stages, err = provider.ListStages(p.Backend(), p.App().Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		resources := []project.InventoryResource{}
+		for _, stage := range stages {
+			items, err := p.Inventory(stage)
+			if err != nil {
+				if errors.Is(err, provider.ErrStateNotFound) {
+					if c.Bool("all-stages") {
+						continue
+					}
+					return project.ErrStageNotFound
+				}
+				return err
+			}
+			resources = append(resources, items...)
+		}
+
+		switch format {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(resources)
+		case "csv":
+			writer := csv.NewWriter(os.Stdout)
+			writer.Write([]string{"stage", "urn", "name", "type", "provider", "id", "region", "account", "parent", "created", "modified"})
+			for _, item := range resources {
+				writer.Write([]string{
+					item.Stage,
+					item.URN,
+					item.Name,
+					item.Type,
+					item.Provider,
+					item.ID,
+					item.Region,
+					item.Account,
+					item.Parent,
+					formatTime(item.Created),
+					formatTime(item.Modified),
+				})
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		columns := []string{"NAME", "TYPE", "PROVIDER", "ID", "REGION", "ACCOUNT", "PARENT", "MODIFIED"}
+		if c.Bool("all-stages") {
+			columns = append([]string{"STAGE"}, columns...)
+		}
+		fmt.Fprintln(writer, strings.Join(columns, "\t"))
+		for _, item := range resources {
+			parent := ""
+			if item.Parent != "" {
+				parent = resource.URN(item.Parent).Name()
+			}
+			row := []string{item.Name, item.Type, item.Provider, item.ID, item.Region, item.Account, parent, formatTime(item.Modified)}
+			if c.Bool("all-stages") {
+				row = append([]string{item.Stage}, row...)
+			}
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	},
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -159,6 +159,7 @@ var CmdSecretLoad = &cli.Command{
 			},
 		},
 	},
+	Audit: true,
 	Run: func(c *cli.Cli) error {
 		filePath := c.Positional(0)
 		p, err := c.InitProject()
@@ -301,8 +302,9 @@ var CmdSecretSet = &cli.Command{
 			},
 		},
 		{
-			Name:     "value",
-			Required: false,
+			Name:      "value",
+			Required:  false,
+			Sensitive: true,
 			Description: cli.Description{
 				Short: "The value of the secret",
 				Long:  "The value of the secret.",
@@ -329,6 +331,7 @@ var CmdSecretSet = &cli.Command{
 			},
 		},
 	},
+	Audit: true,
 	Run: func(c *cli.Cli) error {
 		key := c.Positional(0)
 		value := c.Positional(1)
@@ -448,6 +451,7 @@ var CmdSecretRemove = &cli.Command{
 			},
 		},
 	},
+	Audit: true,
 	Run: func(c *cli.Cli) error {
 		key := c.Positional(0)
 		p, err := c.InitProject()
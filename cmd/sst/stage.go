@@ -0,0 +1,465 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/cmd/sst/mosaic/ui"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/freeze"
+	"github.com/sst/sst/v3/pkg/process"
+	"github.com/sst/sst/v3/pkg/project"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+type stageGC struct {
+	Stage   string `json:"stage"`
+	Expires string `json:"expires"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+var overrideFreezeFlag = cli.Flag{
+	Name: "override-freeze",
+	Type: "string",
+	Description: cli.Description{
+		Short: "Run it during a freeze window",
+		Long:  "Run it during a freeze window of the stage. Takes the reason, which is recorded with the update.",
+	},
+}
+
+var CmdStage = &cli.Command{
+	Name: "stage",
+	Description: cli.Description{
+		Short: "Manage the stages of your app",
+		Long: strings.Join([]string{
+			"Manage the stages of your app.",
+			"",
+			"Stages that are deployed with a `--ttl` expire after that long. This is useful for",
+			"stages that are created for a pull request.",
+			"",
+			"```bash frame=\"none\"",
+			"sst deploy --stage pr-123 --ttl 72h",
+			"```",
+			"",
+			"Expired stages are not removed on their own. Run `sst stage gc` to remove them.",
+			"",
+			"You can also freeze a stage, so it can't be deployed, removed or refreshed for a while.",
+			"",
+			"```bash frame=\"none\"",
+			"sst stage freeze --stage production --start 2026-12-20 --end 2027-01-02 --reason \"Holidays\"",
+			"```",
+		}, "\n"),
+	},
+	Children: []*cli.Command{
+		{
+			Name: "ls",
+			Description: cli.Description{
+				Short: "List the stages of your app",
+				Long: strings.Join([]string{
+					"Lists the stages of your app in your home, along with when they expire.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage ls",
+					"```",
+				}, "\n"),
+			},
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+
+				stages, err := provider.ListStages(p.Backend(), p.App().Name)
+				if err != nil {
+					return err
+				}
+				writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				fmt.Fprintln(writer, "STAGE\tEXPIRES\tSTATUS")
+				for _, stage := range stages {
+					expires, err := provider.GetExpiry(p.Backend(), p.App().Name, stage)
+					if err != nil {
+						return err
+					}
+					locked, err := provider.IsLocked(p.Backend(), p.App().Name, stage)
+					if err != nil {
+						return err
+					}
+					status := ""
+					if !expires.IsZero() && expires.Before(time.Now()) {
+						status = "expired"
+					}
+					if locked {
+						status = "locked"
+					}
+					fmt.Fprintln(writer, stage+"\t"+formatExpiry(expires)+"\t"+status)
+				}
+				return writer.Flush()
+			},
+		},
+		{
+			Name: "freeze",
+			Description: cli.Description{
+				Short: "Freeze the stage",
+				Long: strings.Join([]string{
+					"Adds a freeze window to the stage. During a freeze window, `sst deploy`, `sst remove`,",
+					"`sst refresh` and `sst import` are stopped unless they are run with `--override-freeze`.",
+					"",
+					"The deploys that `sst dev` makes are frozen as well, since they change the stage just",
+					"the same. Start it with `sst dev --override-freeze` to deploy during a freeze window.",
+					"",
+					"A window can be a date range. The end day is included.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage freeze --stage production --start 2026-12-20 --end 2027-01-02 --reason \"Holidays\"",
+					"```",
+					"",
+					"Or it can start on a cron schedule and last for a duration. For example, from Friday",
+					"evening to Monday morning.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage freeze --stage production --cron \"0 18 * * 5\" --duration 62h --timezone America/New_York",
+					"```",
+					"",
+					"Dates and the cron are in UTC unless a `--timezone` is given. The windows are stored in",
+					"your home, so they apply to everyone deploying the stage. Windows can also be set with",
+					"`freeze` in your `sst.config.ts`.",
+					"",
+					"```ts title=\"sst.config.ts\"",
+					"app(input) {",
+					"  return {",
+					"    name: \"my-app\",",
+					"    freeze: input.stage === \"production\" ? [",
+					"      { start: \"2026-12-20\", end: \"2027-01-02\", reason: \"Holidays\" }",
+					"    ] : []",
+					"  };",
+					"}",
+					"```",
+					"",
+					"Without any options, it lists the freeze windows of the stage.",
+				}, "\n"),
+			},
+			Flags: []cli.Flag{
+				{
+					Name: "start",
+					Type: "string",
+					Description: cli.Description{
+						Short: "When the freeze starts",
+						Long:  "The day or time the freeze starts, like `2026-12-20` or `2026-12-20T18:00:00Z`.",
+					},
+				},
+				{
+					Name: "end",
+					Type: "string",
+					Description: cli.Description{
+						Short: "When the freeze ends",
+						Long:  "The day or time the freeze ends. A day is included in the freeze.",
+					},
+				},
+				{
+					Name: "cron",
+					Type: "string",
+					Description: cli.Description{
+						Short: "When the freeze starts, as a cron",
+						Long:  "A cron expression, like `0 18 * * 5`, for when the freeze starts.",
+					},
+				},
+				{
+					Name: "duration",
+					Type: "string",
+					Description: cli.Description{
+						Short: "How long the freeze lasts",
+						Long:  "How long the freeze lasts after the cron, like `62h`.",
+					},
+				},
+				{
+					Name: "timezone",
+					Type: "string",
+					Description: cli.Description{
+						Short: "The timezone of the freeze",
+						Long:  "The timezone of the days and the cron, like `America/New_York`. Defaults to UTC.",
+					},
+				},
+				{
+					Name: "reason",
+					Type: "string",
+					Description: cli.Description{
+						Short: "Why the stage is frozen",
+						Long:  "Why the stage is frozen. This is shown when a deploy is stopped.",
+					},
+				},
+			},
+			Audit: true,
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+
+				window := freeze.Window{
+					Start:    c.String("start"),
+					End:      c.String("end"),
+					Cron:     c.String("cron"),
+					Duration: c.String("duration"),
+					Timezone: c.String("timezone"),
+					Reason:   c.String("reason"),
+				}
+				if window.Start == "" && window.End == "" && window.Cron == "" {
+					windows, err := p.FreezeWindows()
+					if err != nil {
+						return err
+					}
+					if len(windows) == 0 {
+						fmt.Println("The " + p.App().Stage + " stage has no freeze windows")
+						return nil
+					}
+					writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+					fmt.Fprintln(writer, "WINDOW\tREASON\tSTATUS")
+					for _, window := range windows {
+						status := ""
+						until, err := window.Until(time.Now())
+						if err != nil {
+							status = "invalid: " + err.Error()
+						} else if !until.IsZero() {
+							status = "active until " + formatExpiry(until)
+						}
+						fmt.Fprintln(writer, window.String()+"\t"+window.Reason+"\t"+status)
+					}
+					return writer.Flush()
+				}
+				err = window.Validate()
+				if err != nil {
+					return util.NewReadableError(err, "Invalid freeze window: "+err.Error())
+				}
+				windows, err := provider.GetFreeze(p.Backend(), p.App().Name, p.App().Stage)
+				if err != nil {
+					return err
+				}
+				err = provider.PutFreeze(p.Backend(), p.App().Name, p.App().Stage, append(windows, window))
+				if err != nil {
+					return err
+				}
+				ui.Success("Froze " + p.App().Stage + " " + window.String())
+				return nil
+			},
+		},
+		{
+			Name: "unfreeze",
+			Description: cli.Description{
+				Short: "Remove the freeze windows of the stage",
+				Long: strings.Join([]string{
+					"Removes the freeze windows of the stage that were added with `sst stage freeze`.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage unfreeze --stage production",
+					"```",
+					"",
+					"The windows in your `sst.config.ts` still apply.",
+				}, "\n"),
+			},
+			Audit: true,
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+
+				err = provider.RemoveFreeze(p.Backend(), p.App().Name, p.App().Stage)
+				if err != nil {
+					return err
+				}
+				ui.Success("Removed the freeze windows of " + p.App().Stage)
+				if len(p.App().Freeze) > 0 {
+					fmt.Println(ui.TEXT_DIM.Render(fmt.Sprintf("%v freeze window(s) in sst.config.ts still apply", len(p.App().Freeze))))
+				}
+				return nil
+			},
+		},
+		{
+			Name: "gc",
+			Description: cli.Description{
+				Short: "Remove the stages that have expired",
+				Long: strings.Join([]string{
+					"Removes the stages of your app whose `--ttl` has passed.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage gc",
+					"```",
+					"",
+					"The stages are removed one at a time, the same way `sst remove` would. Stages",
+					"that are locked because they are being updated are skipped, and so are stages",
+					"that are protected with `protect` in your `sst.config.ts` or are frozen.",
+					"",
+					"To see which stages would be removed without removing them, use `--dry-run`.",
+					"",
+					"```bash frame=\"none\"",
+					"sst stage gc --dry-run",
+					"```",
+					"",
+					"With `--json`, a report of what happened to each stage is printed once it's done.",
+					"This is useful when running it on a schedule in CI.",
+				}, "\n"),
+			},
+			Flags: []cli.Flag{
+				{
+					Name: "dry-run",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "List the stages without removing them",
+						Long:  "List the stages that have expired without removing them.",
+					},
+				},
+				{
+					Name: "json",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Print a JSON report",
+						Long:  "Print a JSON report of what happened to each stage.",
+					},
+				},
+			},
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+
+				stages, err := provider.ListStages(p.Backend(), p.App().Name)
+				if err != nil {
+					return err
+				}
+				executable, err := os.Executable()
+				if err != nil {
+					return err
+				}
+				// the output of the removes can't mix with the report
+				output := os.Stdout
+				if c.Bool("json") {
+					output = os.Stderr
+				}
+
+				report := []stageGC{}
+				failed := 0
+				for _, stage := range stages {
+					expires, err := provider.GetExpiry(p.Backend(), p.App().Name, stage)
+					if err != nil {
+						return err
+					}
+					if expires.IsZero() || expires.After(time.Now()) {
+						continue
+					}
+					entry := stageGC{
+						Stage:   stage,
+						Expires: formatExpiry(expires),
+					}
+					entry.Status, err = gcStatus(p, stage)
+					if err != nil {
+						entry.Status = "failed"
+						entry.Error = err.Error()
+					}
+					if entry.Status == "expired" && !c.Bool("dry-run") {
+						args := []string{"remove", "--stage", stage}
+						if c.String("config") != "" {
+							args = append(args, "--config", c.String("config"))
+						}
+						cmd := process.CommandContext(c.Context, executable, args...)
+						cmd.Stdout = output
+						cmd.Stderr = os.Stderr
+						cmd.Stdin = os.Stdin
+						entry.Status = "removed"
+						if err := cmd.Run(); err != nil {
+							entry.Status = "failed"
+							entry.Error = err.Error()
+						}
+					}
+					if entry.Status == "failed" {
+						failed++
+					}
+					report = append(report, entry)
+					if !c.Bool("json") {
+						printStageGC(entry)
+					}
+				}
+
+				if c.Bool("json") {
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "  ")
+					err = encoder.Encode(report)
+					if err != nil {
+						return err
+					}
+				} else if len(report) == 0 {
+					ui.Success("No stages have expired")
+				}
+				if failed > 0 {
+					return util.NewReadableError(nil, fmt.Sprintf("Could not remove %v expired stage(s)", failed))
+				}
+				return nil
+			},
+		},
+	},
+}
+
+// gcStatus checks if an expired stage can be removed. The config is loaded
+// for the stage since protect usually depends on it.
+func gcStatus(p *project.Project, stage string) (string, error) {
+	locked, err := provider.IsLocked(p.Backend(), p.App().Name, stage)
+	if err != nil {
+		return "", err
+	}
+	if locked {
+		return "locked", nil
+	}
+	other, err := project.New(&project.ProjectConfig{
+		Version: version,
+		Config:  p.PathConfig(),
+		Stage:   stage,
+	})
+	if err != nil {
+		return "", err
+	}
+	if other.App().Protect {
+		return "protected", nil
+	}
+	windows, err := provider.GetFreeze(p.Backend(), p.App().Name, stage)
+	if err != nil {
+		return "", err
+	}
+	window, _, err := freeze.Active(append(windows, other.App().Freeze...), time.Now())
+	if err != nil {
+		return "", err
+	}
+	if window != nil {
+		return "frozen", nil
+	}
+	return "expired", nil
+}
+
+func printStageGC(entry stageGC) {
+	switch entry.Status {
+	case "removed":
+		ui.Success("Removed " + entry.Stage)
+	case "failed":
+		ui.Error("Failed to remove " + entry.Stage + ": " + entry.Error)
+	case "expired":
+		fmt.Println(ui.TEXT_WARNING_BOLD.Render(indent(entry.Stage)) + ui.TEXT_DIM.Render("expired "+entry.Expires+", would be removed"))
+	default:
+		fmt.Println(ui.TEXT_DIM_BOLD.Render(indent(entry.Stage)) + ui.TEXT_DIM.Render("expired "+entry.Expires+", skipped because it is "+entry.Status))
+	}
+}
+
+func formatExpiry(expires time.Time) string {
+	if expires.IsZero() {
+		return "never"
+	}
+	return expires.UTC().Format(time.RFC3339)
+}
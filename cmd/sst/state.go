@@ -28,6 +28,7 @@ var CmdState = &cli.Command{
 			Description: cli.Description{
 				Short: "Edit the state of your app",
 			},
+			Audit: true,
 			Run: func(c *cli.Cli) error {
 				p, err := c.InitProject()
 				if err != nil {
@@ -259,6 +260,7 @@ var CmdState = &cli.Command{
 					"By default, it runs on your personal stage.",
 				}, "\n"),
 			},
+			Audit: true,
 			Run: func(c *cli.Cli) error {
 				p, err := c.InitProject()
 				if err != nil {
@@ -334,6 +336,8 @@ var CmdState = &cli.Command{
 					"This command does this by going through all the resources in the state, fixing the",
 					"issues and updating the state.",
 					"",
+					"To see the issues without fixing them, run `sst state verify`.",
+					"",
 					"You can run this for specific stages as well.",
 					"",
 					"```bash frame=\"none\"",
@@ -343,6 +347,7 @@ var CmdState = &cli.Command{
 					"By default, it runs on your personal stage.",
 				}, "\n"),
 			},
+			Audit: true,
 			Run: func(c *cli.Cli) error {
 				p, err := c.InitProject()
 				if err != nil {
@@ -405,6 +410,105 @@ var CmdState = &cli.Command{
 				return nil
 			},
 		},
+		{
+			Name: "verify",
+			Description: cli.Description{
+				Short: "Check the state of your app for problems",
+				Long: strings.Join([]string{
+					"Checks the state of your app for problems without changing it.",
+					"",
+					"```bash frame=\"none\"",
+					"sst state verify --stage production",
+					"```",
+					"",
+					"It looks for the following issues.",
+					"",
+					"- `missing-parent`: The parent of a resource is not in the state.",
+					"- `missing-dependency`: A resource depends on one that is not in the state.",
+					"- `missing-property-dependency`: A property of a resource depends on one that is",
+					"  not in the state.",
+					"- `missing-provider`: The provider of a resource is not in the state.",
+					"- `invalid-provider`: The provider reference of a resource can't be read.",
+					"- `duplicate-urn`: A resource is listed more than once.",
+					"- `pending-operation`: An operation on a resource did not complete.",
+					"- `decrypt-failed`: The state can't be decrypted with the passphrase of the stage.",
+					"",
+					"For each issue, it prints what `sst state repair` would do to fix it. Issues that",
+					"repair doesn't fix need the state to be edited by hand.",
+					"",
+					"If any issues are found, it exits with an error. So you can run it in CI.",
+				}, "\n"),
+			},
+			Flags: []cli.Flag{
+				{
+					Name: "json",
+					Type: "bool",
+					Description: cli.Description{
+						Short: "Print the issues as JSON",
+						Long:  "Print the issues as JSON.",
+					},
+				},
+			},
+			Run: func(c *cli.Cli) error {
+				p, err := c.InitProject()
+				if err != nil {
+					return err
+				}
+				defer p.Cleanup()
+				workdir, err := p.NewWorkdir(id.Descending())
+				if err != nil {
+					return err
+				}
+				defer workdir.Cleanup()
+
+				_, err = workdir.Pull()
+				if err != nil {
+					return util.NewReadableError(err, "Could not pull state")
+				}
+				checkpoint, err := workdir.Export()
+				if err != nil {
+					return util.NewReadableError(err, "Could not export state")
+				}
+
+				issues := state.Verify(checkpoint)
+				passphrase, err := provider.Passphrase(p.Backend(), p.App().Name, p.App().Stage)
+				if err != nil {
+					return err
+				}
+				if issue := state.VerifyDecrypt(c.Context, passphrase, checkpoint); issue != nil {
+					issues = append(issues, *issue)
+				}
+
+				if c.Bool("json") {
+					encoder := json.NewEncoder(os.Stdout)
+					encoder.SetIndent("", "  ")
+					if err := encoder.Encode(issues); err != nil {
+						return err
+					}
+				} else {
+					for _, issue := range issues {
+						fmt.Print(ui.TEXT_DANGER_BOLD.Render(string(issue.Code)))
+						if issue.Resource != "" {
+							fmt.Printf(" %s → %s", issue.Resource.Type().DisplayName(), issue.Resource.Name())
+						}
+						fmt.Println()
+						fmt.Println("  " + issue.Message)
+						if issue.Fix != "" {
+							fmt.Println(ui.TEXT_DIM.Render("  Repair: " + issue.Fix))
+						} else {
+							fmt.Println(ui.TEXT_DIM.Render("  Repair: Not fixed by repair"))
+						}
+					}
+				}
+				if len(issues) > 0 {
+					return util.NewReadableError(nil, fmt.Sprintf("Found %d issues in the state", len(issues)))
+				}
+				if !c.Bool("json") {
+					ui.Success("No issues found in the state")
+				}
+				return nil
+			},
+		},
 	},
 }
 
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sst/sst/v3/cmd/sst/cli"
+	"github.com/sst/sst/v3/cmd/sst/mosaic/ui"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/process"
+	"github.com/sst/sst/v3/pkg/workspace"
+)
+
+var allFlag = cli.Flag{
+	Name: "all",
+	Type: "bool",
+	Description: cli.Description{
+		Short: "Run it for every app in the workspace",
+		Long:  "Run it for every app listed in `sst.workspace.json`, or every `sst.config.ts` in the current directory.",
+	},
+}
+
+var concurrencyFlag = cli.Flag{
+	Name: "concurrency",
+	Type: "string",
+	Description: cli.Description{
+		Short: "Limit the number of apps that run at once",
+		Long:  "Limit the number of apps that run at once with `--all`. Defaults to 4.",
+	},
+}
+
+// runWorkspace runs the current command for every app in the workspace, each
+// in its own process so they keep their own lock and state.
+func runWorkspace(c *cli.Cli) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ws, err := workspace.Load(cwd)
+	if err != nil {
+		return err
+	}
+	if len(ws.Apps) == 0 {
+		return util.NewReadableError(nil, "Could not find any sst.config.ts in "+cwd)
+	}
+	order, err := ws.Order()
+	if err != nil {
+		return err
+	}
+
+	concurrency := 4
+	if ws.Concurrency > 0 {
+		concurrency = ws.Concurrency
+	}
+	if c.String("concurrency") != "" {
+		concurrency, err = strconv.Atoi(c.String("concurrency"))
+		if err != nil || concurrency < 1 {
+			return util.NewReadableError(err, "The --concurrency flag must be a positive number")
+		}
+	}
+
+	// every app runs on the same stage, so a personal stage is only resolved
+	// once
+	stage, err := c.Stage(order[0].Config)
+	if err != nil {
+		return util.NewReadableError(err, "Could not find stage")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	command := c.Path()[len(c.Path())-1].Name
+	args := c.Forward("all", "concurrency", "config", "stage")
+
+	renderKeyValue("Stage", stage)
+	renderKeyValue("Apps", order[0].Name)
+	for _, app := range order[1:] {
+		fmt.Println(indent("") + ui.TEXT_INFO.Render(app.Name))
+	}
+	fmt.Println()
+
+	var lock sync.Mutex
+	start := time.Now()
+	results := ws.Run(c.Context, concurrency, command == "remove", func(ctx context.Context, app *workspace.App) error {
+		prefix := ui.TEXT_DIM.Render(app.Name + " | ")
+		stdout := &prefixWriter{lock: &lock, out: os.Stdout, prefix: prefix}
+		stderr := &prefixWriter{lock: &lock, out: os.Stderr, prefix: prefix}
+		defer stdout.Flush()
+		defer stderr.Flush()
+		cmd := process.CommandContext(ctx, executable, append(slices.Clone(args), "--config="+app.Config, "--stage="+stage)...)
+		cmd.Dir = filepath.Dir(app.Config)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	})
+
+	fmt.Println()
+	failed := 0
+	for _, result := range results {
+		switch result.Status {
+		case workspace.StatusSucceeded:
+			fmt.Println(ui.TEXT_SUCCESS_BOLD.Render(ui.IconCheck) + "  " + ui.TEXT_NORMAL_BOLD.Render(result.App) + " " + ui.TEXT_DIM.Render(formatDuration(result.Duration)))
+		case workspace.StatusFailed:
+			failed++
+			fmt.Println(ui.TEXT_DANGER_BOLD.Render(ui.IconX) + "  " + ui.TEXT_NORMAL_BOLD.Render(result.App) + " " + ui.TEXT_DIM.Render(formatDuration(result.Duration)))
+		case workspace.StatusSkipped:
+			fmt.Println(ui.TEXT_DIM_BOLD.Render("-") + "  " + ui.TEXT_NORMAL_BOLD.Render(result.App) + " " + ui.TEXT_DIM.Render("skipped"))
+		}
+	}
+	fmt.Println(ui.TEXT_DIM.Render(fmt.Sprintf("%v apps in %v", len(results), formatDuration(time.Since(start)))))
+	if failed > 0 {
+		return util.NewReadableError(nil, fmt.Sprintf("%v of %v apps failed to %v", failed, len(results), command))
+	}
+	return nil
+}
+
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Second).String()
+}
+
+// prefixWriter prefixes every line with the app it came from. Whole lines are
+// written at once so the output of apps that run at the same time doesn't
+// interleave.
+type prefixWriter struct {
+	lock   *sync.Mutex
+	out    io.Writer
+	prefix string
+	buffer []byte
+}
+
+func (w *prefixWriter) Write(data []byte) (int, error) {
+	w.buffer = append(w.buffer, data...)
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index == -1 {
+			break
+		}
+		w.write(w.buffer[:index+1])
+		w.buffer = w.buffer[index+1:]
+	}
+	return len(data), nil
+}
+
+func (w *prefixWriter) Flush() {
+	if len(w.buffer) > 0 {
+		w.write(append(w.buffer, '\n'))
+		w.buffer = nil
+	}
+}
+
+func (w *prefixWriter) write(line []byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
@@ -8,6 +8,9 @@ var SST_LOG = os.Getenv("SST_LOG")
 var SST_LOG_CHILDREN = isTrue("SST_LOG_CHILDREN")
 var SST_PRINT_LOGS = isTrue("SST_PRINT_LOGS")
 var SST_NO_CLEANUP = isTrue("SST_NO_CLEANUP")
+var SST_NO_CONFIG_CACHE = isTrue("SST_NO_CONFIG_CACHE")
+var SST_STAGE_FROM_BRANCH = isTrue("SST_STAGE_FROM_BRANCH")
+var SST_STAGE_MAP = os.Getenv("SST_STAGE_MAP")
 var SST_PASSPHRASE = os.Getenv("SST_PASSPHRASE")
 var SST_PULUMI_PATH = os.Getenv("SST_PULUMI_PATH")
 
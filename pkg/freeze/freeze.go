@@ -0,0 +1,256 @@
+package freeze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a period where deploys are blocked. It's either a date range,
+// with Start and End, or it starts every time Cron matches and lasts for
+// Duration. Dates can be a day like `2026-12-24`, where the End day is
+// included, or a time like `2026-12-24T18:00:00Z`. Days and the cron are in
+// Timezone, or UTC if it's not set.
+type Window struct {
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Cron     string `json:"cron,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// the longest a cron window can last, so checking it stays cheap
+const maxDuration = 31 * 24 * time.Hour
+
+func (w Window) String() string {
+	result := ""
+	if w.Cron != "" {
+		result = fmt.Sprintf("%v for %v", w.Cron, w.Duration)
+	} else {
+		result = w.Start + " to " + w.End
+	}
+	if w.Timezone != "" {
+		result += " " + w.Timezone
+	}
+	return result
+}
+
+func (w Window) Validate() error {
+	_, err := w.location()
+	if err != nil {
+		return err
+	}
+	if w.Cron != "" {
+		if w.Start != "" || w.End != "" {
+			return fmt.Errorf("a freeze window can have a cron or a start and end, not both")
+		}
+		_, err := parseCron(w.Cron)
+		if err != nil {
+			return err
+		}
+		_, err = w.duration()
+		return err
+	}
+	if w.Start == "" || w.End == "" {
+		return fmt.Errorf("a freeze window needs a cron or a start and end")
+	}
+	start, end, err := w.dates()
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		return fmt.Errorf("the freeze window %v ends before it starts", w)
+	}
+	return nil
+}
+
+// Until returns when the window that is active at the given time ends, or a
+// zero time if it isn't active.
+func (w Window) Until(now time.Time) (time.Time, error) {
+	if err := w.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	if w.Cron == "" {
+		start, end, _ := w.dates()
+		if now.Before(start) || !now.Before(end) {
+			return time.Time{}, nil
+		}
+		return end, nil
+	}
+	cron, _ := parseCron(w.Cron)
+	duration, _ := w.duration()
+	location, _ := w.location()
+	// look for the latest start within the duration, a minute at a time
+	current := now.In(location).Truncate(time.Minute)
+	oldest := now.Add(-duration)
+	for current.After(oldest) {
+		if cron.matches(current) {
+			return current.Add(duration), nil
+		}
+		current = current.Add(-time.Minute)
+	}
+	return time.Time{}, nil
+}
+
+func (w Window) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", w.Timezone)
+	}
+	return location, nil
+}
+
+func (w Window) duration() (time.Duration, error) {
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 || duration > maxDuration {
+		return 0, fmt.Errorf("the freeze window %v needs a duration like 24h, up to %v", w.Cron, maxDuration)
+	}
+	return duration, nil
+}
+
+func (w Window) dates() (time.Time, time.Time, error) {
+	location, err := w.location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start, _, err := parseDate(w.Start, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, day, err := parseDate(w.End, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if day {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// parseDate parses a day or a time, and returns if it was a day
+func parseDate(input string, location *time.Location) (time.Time, bool, error) {
+	parsed, err := time.ParseInLocation("2006-01-02", input, location)
+	if err == nil {
+		return parsed, true, nil
+	}
+	parsed, err = time.Parse(time.RFC3339, input)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q should be a day like 2026-12-24 or a time like 2026-12-24T18:00:00Z", input)
+	}
+	return parsed, false, nil
+}
+
+// Active returns the first window that is active at the given time and when
+// it ends.
+func Active(windows []Window, now time.Time) (*Window, time.Time, error) {
+	for i := range windows {
+		until, err := windows[i].Until(now)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if !until.IsZero() {
+			return &windows[i], until, nil
+		}
+	}
+	return nil, time.Time{}, nil
+}
+
+type cron struct {
+	minute, hour, day, month, weekday []bool
+	anyDay, anyWeekday                bool
+}
+
+func (c *cron) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	day := c.day[t.Day()]
+	weekday := c.weekday[int(t.Weekday())]
+	// like cron, if both are restricted either one can match
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// parseCron parses the five fields of a cron expression: minute, hour, day of
+// the month, month and day of the week. Each field can be `*`, a value, a
+// range like `1-5`, a step like `*/15` or a list of these.
+func parseCron(input string) (*cron, error) {
+	fields := strings.Fields(input)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%q should have five fields: minute, hour, day of the month, month and day of the week", input)
+	}
+	result := &cron{}
+	var err error
+	if result.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if result.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if result.day, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if result.month, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if result.weekday, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// 7 is sunday as well
+	result.weekday[0] = result.weekday[0] || result.weekday[7]
+	result.anyDay = fields[2] == "*"
+	result.anyWeekday = fields[4] == "*"
+	return result, nil
+}
+
+func parseField(input string, min, max int) ([]bool, error) {
+	result := make([]bool, max+1)
+	for _, part := range strings.Split(input, ",") {
+		step := 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			parsed, err := strconv.Atoi(after)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid step in %q", input)
+			}
+			step = parsed
+			part = before
+		}
+		start, end := min, max
+		if part != "*" {
+			before, after, ok := strings.Cut(part, "-")
+			parsed, err := strconv.Atoi(before)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in %q", input)
+			}
+			start, end = parsed, parsed
+			if ok {
+				end, err = strconv.Atoi(after)
+				if err != nil {
+					return nil, fmt.Errorf("invalid range in %q", input)
+				}
+			} else if step > 1 {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("%q is out of range, it should be between %v and %v", input, min, max)
+		}
+		for i := start; i <= end; i += step {
+			result[i] = true
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package freeze_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sst/sst/v3/pkg/freeze"
+)
+
+func TestUntil(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	holidays := freeze.Window{Start: "2026-12-20", End: "2027-01-02"}
+	weekend := freeze.Window{Cron: "0 18 * * 5", Duration: "62h"}
+	tests := []struct {
+		window   freeze.Window
+		now      string
+		expected string
+	}{
+		{holidays, "2026-12-19T23:59:00Z", ""},
+		{holidays, "2026-12-20T00:00:00Z", "2027-01-03T00:00:00Z"},
+		{holidays, "2027-01-02T23:00:00Z", "2027-01-03T00:00:00Z"},
+		{holidays, "2027-01-03T00:00:00Z", ""},
+		// friday the 23rd of october
+		{weekend, "2026-10-23T17:59:00Z", ""},
+		{weekend, "2026-10-23T18:00:00Z", "2026-10-26T08:00:00Z"},
+		{weekend, "2026-10-25T12:00:00Z", "2026-10-26T08:00:00Z"},
+		{weekend, "2026-10-26T08:00:00Z", ""},
+	}
+	for _, test := range tests {
+		until, err := test.window.Until(at(test.now))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.expected == "" && !until.IsZero() {
+			t.Errorf("expected %v to not be frozen at %v, got %v", test.window, test.now, until)
+		}
+		if test.expected != "" && !until.Equal(at(test.expected)) {
+			t.Errorf("expected %v to be frozen at %v until %v, got %v", test.window, test.now, test.expected, until)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []freeze.Window{
+		{},
+		{Start: "2026-12-20"},
+		{Start: "2027-01-02", End: "2026-12-20"},
+		{Start: "tomorrow", End: "2026-12-20"},
+		{Cron: "0 18 * *", Duration: "1h"},
+		{Cron: "0 24 * * *", Duration: "1h"},
+		{Cron: "0 18 * * 5"},
+		{Cron: "0 18 * * 5", Duration: "1h", Start: "2026-12-20"},
+		{Cron: "0 18 * * 5", Duration: "1h", Timezone: "Mars/Olympus"},
+	}
+	for _, window := range invalid {
+		if window.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", window)
+		}
+	}
+	valid := []freeze.Window{
+		{Start: "2026-12-20T18:00:00Z", End: "2026-12-21"},
+		{Cron: "*/30 9-17 1,15 * 1-5", Duration: "15m", Timezone: "America/New_York"},
+	}
+	for _, window := range valid {
+		if err := window.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid, got %v", window, err)
+		}
+	}
+}
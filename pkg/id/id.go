@@ -3,6 +3,7 @@ package id
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"time"
 )
 
@@ -39,3 +40,20 @@ func generateID(descending bool) string {
 
 	return string(result)
 }
+
+// DescendingTime returns when a descending ID was generated, to the
+// millisecond.
+func DescendingTime(id string) (time.Time, error) {
+	if len(id) < 12 {
+		return time.Time{}, fmt.Errorf("invalid id %v", id)
+	}
+	timeBytes, err := hex.DecodeString(id[:12])
+	if err != nil {
+		return time.Time{}, err
+	}
+	var value int64
+	for _, b := range timeBytes {
+		value = value<<8 | int64(b)
+	}
+	return time.UnixMilli(^value & (1<<48 - 1)), nil
+}
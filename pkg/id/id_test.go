@@ -37,3 +37,18 @@ func TestDescending(t *testing.T) {
 	run(t, id.Descending, func(a, b string) bool { return a >= b }, "descending")
 }
 
+func TestDescendingTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	generated := id.Descending()
+	after := time.Now()
+	created, err := id.DescendingTime(generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Before(before) || created.After(after) {
+		t.Fatalf("expected a time between %v and %v, got %v", before, after, created)
+	}
+	if _, err := id.DescendingTime("nothex"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
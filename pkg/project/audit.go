@@ -0,0 +1,59 @@
+package project
+
+import (
+	"log/slog"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/sst/sst/v3/pkg/process"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+// Audit records a command that changed the stage in the home, along with who
+// ran it and the commit it was run from.
+func (p *Project) Audit(record *provider.AuditRecord) error {
+	record.App = p.app.Name
+	record.Stage = p.app.Stage
+	if current, err := user.Current(); err == nil {
+		record.User = current.Username
+	}
+	record.Commit, record.Dirty = gitCommit(p.PathRoot())
+	record.Identity = provider.Identity(p.home)
+	return provider.PutAudit(p.home, record)
+}
+
+// auditDev records a deploy made by `sst dev`. These run on every change while
+// dev is running so they are recorded here instead of by the CLI command.
+func (p *Project) auditDev(started time.Time, err error) {
+	record := &provider.AuditRecord{
+		Command:  "dev",
+		Args:     []string{},
+		Version:  p.Version(),
+		Result:   provider.AuditResultSucceeded,
+		Started:  started.UTC(),
+		Duration: time.Since(started),
+	}
+	if err != nil {
+		record.Result = provider.AuditResultFailed
+		record.Error = err.Error()
+	}
+	if err := p.Audit(record); err != nil {
+		slog.Error("failed to write audit record", "err", err)
+	}
+}
+
+// gitCommit returns the commit checked out in the directory and if there are
+// any uncommitted changes.
+func gitCommit(dir string) (string, bool) {
+	cmd := process.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	status := process.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	changes, err := status.Output()
+	return strings.TrimSpace(string(output)), err == nil && len(strings.TrimSpace(string(changes))) > 0
+}
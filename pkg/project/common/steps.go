@@ -0,0 +1,37 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// ReadSteps reads a pulumi event log and returns the steps it planned in the
+// order they were emitted.
+func ReadSteps(reader io.Reader) ([]apitype.StepEventMetadata, error) {
+	result := []apitype.StepEventMetadata{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event events.EngineEvent
+		err := json.Unmarshal(line, &event)
+		if err != nil {
+			return nil, err
+		}
+		if event.ResourcePreEvent == nil {
+			continue
+		}
+		result = append(result, event.ResourcePreEvent.Metadata)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
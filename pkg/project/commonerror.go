@@ -0,0 +1,124 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// COMMON_ERRORS_FILE lets a project add its own rules on top of CommonErrors.
+const COMMON_ERRORS_FILE = "sst.errors.json"
+
+type Matcher struct {
+	Contains string `json:"contains,omitempty"`
+	Regex    string `json:"regex,omitempty"`
+	regex    *regexp.Regexp
+}
+
+func (m *Matcher) compile() error {
+	if m.Regex == "" {
+		return nil
+	}
+	compiled, err := regexp.Compile(m.Regex)
+	if err != nil {
+		return err
+	}
+	m.regex = compiled
+	return nil
+}
+
+func (m *Matcher) matches(value string) bool {
+	if m.Contains != "" && !strings.Contains(value, m.Contains) {
+		return false
+	}
+	if m.regex != nil && !m.regex.MatchString(value) {
+		return false
+	}
+	return true
+}
+
+// LoadCommonErrors returns the rules from the project's rules file followed by
+// the built in ones.
+func LoadCommonErrors(root string) ([]CommonError, error) {
+	local := []CommonError{}
+	data, err := os.ReadFile(filepath.Join(root, COMMON_ERRORS_FILE))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &local)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", COMMON_ERRORS_FILE, err)
+		}
+	}
+	for i, rule := range local {
+		if rule.Code == "" {
+			return nil, fmt.Errorf("%v: rule %d is missing a code", COMMON_ERRORS_FILE, i+1)
+		}
+	}
+	result := append(local, CommonErrors...)
+	for i := range result {
+		rule := &result[i]
+		// the built in rules share their matchers with CommonErrors
+		if rule.Match != nil {
+			match := *rule.Match
+			rule.Match = &match
+		}
+		if rule.Type != nil {
+			match := *rule.Type
+			rule.Type = &match
+		}
+		if rule.Match == nil {
+			if rule.Message == "" {
+				return nil, fmt.Errorf("%v: rule %v needs a message or a match", COMMON_ERRORS_FILE, rule.Code)
+			}
+			rule.Match = &Matcher{Contains: rule.Message}
+		}
+		for _, matcher := range []*Matcher{rule.Match, rule.Type} {
+			if matcher == nil {
+				continue
+			}
+			err := matcher.compile()
+			if err != nil {
+				return nil, fmt.Errorf("%v: rule %v: %w", COMMON_ERRORS_FILE, rule.Code, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// MatchCommonErrors returns the rules that match an error reported for the
+// given resource. The urn can be empty for errors that aren't about a
+// resource, in which case rules that match on the type are skipped.
+func MatchCommonErrors(rules []CommonError, message string, urn string) []CommonError {
+	result := []CommonError{}
+	for _, rule := range rules {
+		if rule.Match != nil && !rule.Match.matches(message) {
+			continue
+		}
+		if rule.Type != nil {
+			if urn == "" || !rule.Type.matches(string(resource.URN(urn).Type())) {
+				continue
+			}
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// Help is what gets printed under an error that matches the rule.
+func (rule CommonError) Help() []string {
+	result := append([]string{}, rule.Short...)
+	if rule.Command != "" {
+		result = append(result, "Try running `"+rule.Command+"`")
+	}
+	if rule.Docs != "" {
+		result = append(result, "Learn more about this "+rule.Docs)
+	}
+	return result
+}
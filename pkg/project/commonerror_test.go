@@ -0,0 +1,130 @@
+package project_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestMatchCommonErrors(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, project.COMMON_ERRORS_FILE), []byte(`[
+		{
+			"code": "LegacyVpc",
+			"match": { "contains": "InvalidSubnetID.NotFound" },
+			"type": { "regex": "^aws:ec2" },
+			"short": ["The legacy VPC was removed"],
+			"command": "sst refresh"
+		}
+	]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := project.LoadCommonErrors(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		urn     string
+		codes   []string
+	}{
+		{
+			name:    "local rule",
+			message: "creating EC2 Instance: InvalidSubnetID.NotFound: The subnet ID 'subnet-1' does not exist",
+			urn:     "urn:pulumi:dev::app::sst:aws:Vpc$aws:ec2/instance:Instance::Bastion",
+			codes:   []string{"LegacyVpc"},
+		},
+		{
+			name:    "wrong type",
+			message: "InvalidSubnetID.NotFound",
+			urn:     "urn:pulumi:dev::app::aws:lambda/function:Function::Api",
+			codes:   []string{},
+		},
+		{
+			name:    "type without urn",
+			message: "InvalidSubnetID.NotFound",
+			codes:   []string{},
+		},
+		{
+			name:    "built in message",
+			message: "updating CloudFront Distribution: TooManyCacheBehaviors: Your request contains more CacheBehaviors than are allowed per distribution",
+			urn:     "urn:pulumi:dev::app::aws:cloudfront/distribution:Distribution::Cdn",
+			codes:   []string{"TooManyCacheBehaviors"},
+		},
+		{
+			name:    "built in regex",
+			message: "operation error S3: PutObject, api error ExpiredToken: The provided token has expired.",
+			codes:   []string{"ExpiredToken"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matched := project.MatchCommonErrors(rules, test.message, test.urn)
+			if len(matched) != len(test.codes) {
+				t.Fatalf("expected %v, got %v", test.codes, matched)
+			}
+			for i, rule := range matched {
+				if rule.Code != test.codes[i] {
+					t.Errorf("expected %v, got %v", test.codes[i], rule.Code)
+				}
+			}
+		})
+	}
+
+	help := rules[0].Help()
+	if help[len(help)-1] != "Try running `sst refresh`" {
+		t.Errorf("unexpected help %v", help)
+	}
+}
+
+func TestLoadCommonErrorsInvalidRegex(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, project.COMMON_ERRORS_FILE), []byte(`[
+		{ "code": "Broken", "match": { "regex": "(" } }
+	]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = project.LoadCommonErrors(dir)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestLoadCommonErrorsKeepsBuiltIn(t *testing.T) {
+	before := map[string]project.Matcher{}
+	for _, rule := range project.CommonErrors {
+		if rule.Match != nil {
+			before[rule.Code] = *rule.Match
+		}
+	}
+	_, err := project.LoadCommonErrors(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range project.CommonErrors {
+		if rule.Match != nil && *rule.Match != before[rule.Code] {
+			t.Errorf("expected %v to be left as is", rule.Code)
+		}
+	}
+}
+
+func TestLoadCommonErrorsMissingCode(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, project.COMMON_ERRORS_FILE), []byte(`[
+		{ "code": "First", "message": "first" },
+		{ "message": "second" }
+	]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = project.LoadCommonErrors(dir)
+	if err == nil || err.Error() != project.COMMON_ERRORS_FILE+": rule 2 is missing a code" {
+		t.Fatalf("expected the second rule to be missing a code, got %v", err)
+	}
+}
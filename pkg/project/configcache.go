@@ -0,0 +1,144 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sst/sst/v3/pkg/flag"
+	"github.com/sst/sst/v3/pkg/js"
+)
+
+// configCache is what the app function in the config returned the last time
+// it was evaluated for a stage, along with the files it was built from.
+type configCache struct {
+	Hash  string          `json:"hash"`
+	Files []string        `json:"files"`
+	App   json.RawMessage `json:"app"`
+}
+
+// these are set by the shell and differ between terminals
+var shellEnv = []string{
+	"_",
+	"PWD",
+	"OLDPWD",
+	"SHLVL",
+	"TERM_SESSION_ID",
+	"WINDOWID",
+}
+
+func (proj *Project) configCachePath(stage string) string {
+	return filepath.Join(proj.PathWorkingDir(), "cache", "config", stage+".json")
+}
+
+// configHash hashes everything the app function is evaluated with: the config
+// along with everything it imports, the stage, the environment and the CLI
+// version.
+func (proj *Project) configHash(input *ProjectConfig, files []string) (string, error) {
+	filesHash, err := hashFiles(proj.PathRoot(), files)
+	if err != nil {
+		return "", err
+	}
+	env := []string{}
+	for _, item := range os.Environ() {
+		key, _, _ := strings.Cut(item, "=")
+		if slices.Contains(volatileEnv, key) || slices.Contains(shellEnv, key) {
+			continue
+		}
+		env = append(env, item)
+	}
+	slices.Sort(env)
+	hash := sha256.New()
+	for _, item := range []string{input.Version, input.Stage, input.Config, filesHash} {
+		hash.Write([]byte(item))
+		hash.Write([]byte{0})
+	}
+	for _, item := range env {
+		hash.Write([]byte(item))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (proj *Project) readConfigCache(input *ProjectConfig) ([]byte, bool) {
+	if flag.SST_NO_CONFIG_CACHE {
+		return nil, false
+	}
+	data, err := os.ReadFile(proj.configCachePath(input.Stage))
+	if err != nil {
+		return nil, false
+	}
+	var cached configCache
+	err = json.Unmarshal(data, &cached)
+	if err != nil || len(cached.Files) == 0 {
+		return nil, false
+	}
+	hash, err := proj.configHash(input, cached.Files)
+	if err != nil || hash != cached.Hash {
+		slog.Info("config cache is stale", "stage", input.Stage)
+		return nil, false
+	}
+	return cached.App, true
+}
+
+// readConfigCacheAny returns the cached config of the stage without checking
+// if it's still up to date.
+func (proj *Project) readConfigCacheAny(stage string) ([]byte, bool) {
+	data, err := os.ReadFile(proj.configCachePath(stage))
+	if err != nil {
+		return nil, false
+	}
+	var cached configCache
+	err = json.Unmarshal(data, &cached)
+	if err != nil || len(cached.App) == 0 {
+		return nil, false
+	}
+	return cached.App, true
+}
+
+// writeConfigCache records the files from the esbuild metafile so the cache
+// can be checked without building the config again.
+func (proj *Project) writeConfigCache(input *ProjectConfig, metafile string, app []byte) error {
+	if flag.SST_NO_CONFIG_CACHE {
+		return nil
+	}
+	var meta js.Metafile
+	err := json.Unmarshal([]byte(metafile), &meta)
+	if err != nil {
+		return err
+	}
+	files := []string{}
+	for key := range meta.Inputs {
+		path, err := filepath.Abs(key)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		files = append(files, path)
+	}
+	hash, err := proj.configHash(input, files)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(configCache{
+		Hash:  hash,
+		Files: files,
+		App:   app,
+	})
+	if err != nil {
+		return err
+	}
+	path := proj.configCachePath(input.Stage)
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
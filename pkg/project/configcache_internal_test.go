@@ -0,0 +1,112 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, root string, input *ProjectConfig)
+		hit    bool
+	}{
+		{
+			name:   "unchanged",
+			change: func(t *testing.T, root string, input *ProjectConfig) {},
+			hit:    true,
+		},
+		{
+			name: "config",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				writeTestFile(t, input.Config, "export default $config({ app() { return { name: \"other\" } } })")
+			},
+		},
+		{
+			name: "imported file",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				writeTestFile(t, filepath.Join(root, "stages.ts"), "export const stages = [\"dev\"]")
+			},
+		},
+		{
+			name: "env",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				t.Setenv("SST_CONFIG_CACHE_TEST", "changed")
+			},
+		},
+		{
+			name: "shell env",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				t.Setenv("OLDPWD", t.TempDir())
+			},
+			hit: true,
+		},
+		{
+			name: "version",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				input.Version = "3.0.1"
+			},
+		},
+		{
+			name: "stage",
+			change: func(t *testing.T, root string, input *ProjectConfig) {
+				proj := &Project{root: root}
+				data, err := os.ReadFile(proj.configCachePath(input.Stage))
+				if err != nil {
+					t.Fatal(err)
+				}
+				input.Stage = "production"
+				// even the cache of another stage copied over is stale
+				writeTestFile(t, proj.configCachePath(input.Stage), string(data))
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			config := filepath.Join(root, "sst.config.ts")
+			imported := filepath.Join(root, "stages.ts")
+			writeTestFile(t, config, "export default $config({ app() { return { name: \"app\" } } })")
+			writeTestFile(t, imported, "export const stages = [\"production\"]")
+			t.Setenv("SST_CONFIG_CACHE_TEST", "original")
+
+			proj := &Project{root: root}
+			input := &ProjectConfig{Version: "3.0.0", Stage: "dev", Config: config}
+			metafile, err := json.Marshal(map[string]interface{}{
+				"inputs": map[string]interface{}{
+					config:   map[string]interface{}{},
+					imported: map[string]interface{}{},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = proj.writeConfigCache(input, string(metafile), []byte(`{"name":"app"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			test.change(t, root, input)
+			app, hit := proj.readConfigCache(input)
+			if hit != test.hit {
+				t.Fatalf("expected hit to be %v, got %v", test.hit, hit)
+			}
+			if hit && string(app) != `{"name":"app"}` {
+				t.Fatalf("unexpected app %s", app)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+package project_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestNewCached(t *testing.T) {
+	root := t.TempDir()
+	config := filepath.Join(root, "sst.config.ts")
+	if err := os.WriteFile(config, []byte("throw new Error('should not run')"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	input := &project.ProjectConfig{Version: "dev", Stage: "production", Config: config}
+
+	_, err := project.NewCached(input, "", "")
+	if !errors.Is(err, project.ErrConfigNotCached) {
+		t.Fatalf("expected ErrConfigNotCached, got %v", err)
+	}
+
+	p, err := project.NewCached(input, "my-app", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.App().Name != "my-app" || p.App().Home != "local" || p.App().Stage != "production" {
+		t.Fatalf("unexpected app %+v", p.App())
+	}
+
+	cache := filepath.Join(root, ".sst", "cache", "config", "production.json")
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"hash":"stale","files":["` + filepath.ToSlash(config) + `"],"app":{"name":"cached-app","home":"aws","providers":{"aws":{"region":"us-west-2"}}}}`
+	if err := os.WriteFile(cache, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = project.NewCached(input, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.App().Name != "cached-app" || p.App().Home != "aws" {
+		t.Fatalf("unexpected app %+v", p.App())
+	}
+	region := p.App().Providers["aws"].(map[string]interface{})["region"]
+	if region != "us-west-2" {
+		t.Fatalf("expected the cached providers, got %v", p.App().Providers)
+	}
+
+	p, err = project.NewCached(input, "other-app", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.App().Name != "other-app" || p.App().Home != "local" {
+		t.Fatalf("expected the flags to win, got %+v", p.App())
+	}
+	if _, ok := p.App().Providers["aws"]; ok {
+		t.Fatalf("expected the providers of the cached home to be dropped, got %v", p.App().Providers)
+	}
+}
@@ -0,0 +1,71 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/bus"
+	"github.com/sst/sst/v3/pkg/freeze"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+// FreezeEvent is published when an update runs during a freeze window because
+// it was overridden.
+type FreezeEvent struct {
+	Window   freeze.Window
+	Until    time.Time
+	Override string
+}
+
+type ErrFrozen struct {
+	Stage  string
+	Window freeze.Window
+	Until  time.Time
+}
+
+func (err *ErrFrozen) Error() string {
+	return fmt.Sprintf("stage %v is frozen until %v", err.Stage, err.Until.Format(time.RFC3339))
+}
+
+// FreezeWindows are the freeze windows of the stage, from the config and the
+// home.
+func (p *Project) FreezeWindows() ([]freeze.Window, error) {
+	stored, err := provider.GetFreeze(p.home, p.app.Name, p.app.Stage)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]freeze.Window{}, p.app.Freeze...), stored...), nil
+}
+
+// checkFreeze stops the update if the stage is in a freeze window, unless it
+// was overridden with a reason. It returns the reason if it was overridden so
+// it can be recorded with the update.
+func (p *Project) checkFreeze(override string) (string, error) {
+	windows, err := p.FreezeWindows()
+	if err != nil {
+		return "", err
+	}
+	window, until, err := freeze.Active(windows, time.Now())
+	if err != nil {
+		return "", util.NewReadableError(err, "Invalid freeze window: "+err.Error())
+	}
+	if window == nil {
+		return "", nil
+	}
+	override = strings.TrimSpace(override)
+	if override == "" {
+		return "", &ErrFrozen{
+			Stage:  p.app.Stage,
+			Window: *window,
+			Until:  until,
+		}
+	}
+	bus.Publish(&FreezeEvent{
+		Window:   *window,
+		Until:    until,
+		Override: override,
+	})
+	return override, nil
+}
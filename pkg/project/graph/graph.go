@@ -0,0 +1,339 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+type EdgeKind string
+
+const (
+	EdgeParent     EdgeKind = "parent"
+	EdgeDependency EdgeKind = "dependency"
+	EdgeProperty   EdgeKind = "property"
+	EdgeLink       EdgeKind = "link"
+)
+
+type Node struct {
+	URN    string `json:"urn"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// Edge points from a resource to the one it depends on, or for parent edges,
+// from a child to its parent.
+type Edge struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Kind     EdgeKind `json:"kind"`
+	Property string   `json:"property,omitempty"`
+}
+
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// these are in every stage and only add noise
+func hidden(kind string) bool {
+	return kind == "pulumi:pulumi:Stack" ||
+		kind == "sst:sst:LinkRef" ||
+		kind == "sst:sst:Version" ||
+		strings.HasPrefix(kind, "pulumi:providers:")
+}
+
+// New builds the graph of the resources in a checkpoint from their parents,
+// dependencies and property dependencies.
+func New(resources []apitype.ResourceV3) *Graph {
+	g := &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	for _, res := range resources {
+		if hidden(string(res.Type)) {
+			continue
+		}
+		g.Nodes = append(g.Nodes, Node{
+			URN:    string(res.URN),
+			Name:   res.URN.Name(),
+			Type:   string(res.Type),
+			Parent: string(res.Parent),
+		})
+	}
+	for _, res := range resources {
+		if hidden(string(res.Type)) {
+			continue
+		}
+		if res.Parent != "" {
+			g.Edges = append(g.Edges, Edge{From: string(res.URN), To: string(res.Parent), Kind: EdgeParent})
+		}
+		for _, dep := range res.Dependencies {
+			g.Edges = append(g.Edges, Edge{From: string(res.URN), To: string(dep), Kind: EdgeDependency})
+		}
+		properties := []string{}
+		for property := range res.PropertyDependencies {
+			properties = append(properties, string(property))
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			for _, dep := range res.PropertyDependencies[resource.PropertyKey(property)] {
+				g.Edges = append(g.Edges, Edge{From: string(res.URN), To: string(dep), Kind: EdgeProperty, Property: property})
+			}
+		}
+	}
+	return g.prune()
+}
+
+// Links builds the graph of which components are linked to which resources.
+// Functions record the names of their links in their metadata and everything
+// else that runs code, like services, does through their dev command.
+func Links(resources []apitype.ResourceV3, targets []string) *Graph {
+	g := &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	byURN := map[string]apitype.ResourceV3{}
+	byName := map[string]string{}
+	for _, res := range resources {
+		byURN[string(res.URN)] = res
+		if res.Parent != "" && byURN[string(res.Parent)].Type == "pulumi:pulumi:Stack" {
+			byName[res.URN.Name()] = string(res.URN)
+		}
+	}
+	added := map[string]bool{}
+	add := func(urn string) {
+		if added[urn] {
+			return
+		}
+		added[urn] = true
+		res := byURN[urn]
+		g.Nodes = append(g.Nodes, Node{
+			URN:  urn,
+			Name: res.URN.Name(),
+			Type: string(res.Type),
+		})
+	}
+	for _, res := range resources {
+		source := string(res.URN)
+		links := stringList(res.Outputs, "_metadata", "links")
+		if dev := stringList(res.Outputs, "_dev", "links"); len(dev) > 0 {
+			links = dev
+			// dev commands are created by the component that runs them
+			if parent, ok := byURN[string(res.Parent)]; ok && parent.Type != "pulumi:pulumi:Stack" {
+				source = string(parent.URN)
+			}
+		}
+		for _, link := range links {
+			target, ok := byName[link]
+			if !ok || !slices.Contains(targets, link) {
+				continue
+			}
+			add(source)
+			add(target)
+			g.Edges = append(g.Edges, Edge{From: source, To: target, Kind: EdgeLink})
+		}
+	}
+	return g.dedupe()
+}
+
+func stringList(outputs map[string]interface{}, key string, field string) []string {
+	result := []string{}
+	parent, ok := outputs[key].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	items, ok := parent[field].([]interface{})
+	if !ok {
+		return result
+	}
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Root keeps the components with the given name and everything under them.
+func (g *Graph) Root(name string) *Graph {
+	keep := map[string]bool{}
+	for _, node := range g.Nodes {
+		if node.Name == name {
+			keep[node.URN] = true
+		}
+	}
+	// parents always come before their children
+	for _, node := range g.Nodes {
+		if keep[node.Parent] {
+			keep[node.URN] = true
+		}
+	}
+	return g.filter(func(node Node) bool {
+		return keep[node.URN]
+	})
+}
+
+// Types keeps the resources that match one of the types. Types can use `*`
+// as a wildcard, like `aws:s3*`.
+func (g *Graph) Types(types []string) *Graph {
+	patterns := []*regexp.Regexp{}
+	for _, kind := range types {
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(kind), `\*`, ".*") + "$"
+		patterns = append(patterns, regexp.MustCompile(pattern))
+	}
+	return g.filter(func(node Node) bool {
+		return slices.ContainsFunc(patterns, func(pattern *regexp.Regexp) bool {
+			return pattern.MatchString(node.Type)
+		})
+	})
+}
+
+// Collapse replaces every resource with the top level component it belongs
+// to, so only the dependencies between components are left.
+func (g *Graph) Collapse() *Graph {
+	parents := map[string]string{}
+	for _, node := range g.Nodes {
+		parents[node.URN] = node.Parent
+	}
+	top := func(urn string) string {
+		for {
+			parent, ok := parents[urn]
+			if !ok || parent == "" {
+				return urn
+			}
+			if _, ok := parents[parent]; !ok {
+				return urn
+			}
+			urn = parent
+		}
+	}
+	result := &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	for _, node := range g.Nodes {
+		if top(node.URN) == node.URN {
+			node.Parent = ""
+			result.Nodes = append(result.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if edge.Kind == EdgeParent {
+			continue
+		}
+		from := top(edge.From)
+		to := top(edge.To)
+		if from == to {
+			continue
+		}
+		kind := edge.Kind
+		if kind == EdgeProperty {
+			kind = EdgeDependency
+		}
+		result.Edges = append(result.Edges, Edge{From: from, To: to, Kind: kind})
+	}
+	return result.dedupe()
+}
+
+func (g *Graph) filter(keep func(node Node) bool) *Graph {
+	result := &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	for _, node := range g.Nodes {
+		if keep(node) {
+			result.Nodes = append(result.Nodes, node)
+		}
+	}
+	result.Edges = g.Edges
+	return result.prune()
+}
+
+// prune drops the edges to resources that aren't in the graph.
+func (g *Graph) prune() *Graph {
+	exists := map[string]bool{}
+	for _, node := range g.Nodes {
+		exists[node.URN] = true
+	}
+	edges := []Edge{}
+	for _, edge := range g.Edges {
+		if exists[edge.From] && exists[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	g.Edges = edges
+	return g
+}
+
+func (g *Graph) dedupe() *Graph {
+	seen := map[Edge]bool{}
+	edges := []Edge{}
+	for _, edge := range g.Edges {
+		if seen[edge] {
+			continue
+		}
+		seen[edge] = true
+		edges = append(edges, edge)
+	}
+	g.Edges = edges
+	return g
+}
+
+func (g *Graph) DOT(title string) string {
+	lines := []string{
+		fmt.Sprintf("digraph %q {", title),
+		"  rankdir=LR;",
+		"  node [shape=box];",
+	}
+	for _, node := range g.Nodes {
+		lines = append(lines, fmt.Sprintf("  %q [label=%q];", node.URN, node.Name+"\n"+node.Type))
+	}
+	for _, edge := range g.Edges {
+		attributes := ""
+		switch edge.Kind {
+		case EdgeParent:
+			attributes = " [style=dashed]"
+		case EdgeProperty:
+			attributes = fmt.Sprintf(" [label=%q]", edge.Property)
+		case EdgeLink:
+			attributes = " [color=blue]"
+		}
+		lines = append(lines, fmt.Sprintf("  %q -> %q%v;", edge.From, edge.To, attributes))
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (g *Graph) Mermaid() string {
+	ids := map[string]string{}
+	lines := []string{"graph LR"}
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.URN] = id
+		lines = append(lines, fmt.Sprintf("  %v[\"%v<br/>%v\"]", id, mermaidEscape(node.Name), mermaidEscape(node.Type)))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Kind {
+		case EdgeParent:
+			arrow = "-.->"
+		case EdgeProperty:
+			arrow = "-- \"" + mermaidEscape(edge.Property) + "\" -->"
+		case EdgeLink:
+			arrow = "==>"
+		}
+		lines = append(lines, fmt.Sprintf("  %v %v %v", ids[edge.From], arrow, ids[edge.To]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func mermaidEscape(input string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(input)
+}
@@ -0,0 +1,68 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/pkg/project/graph"
+)
+
+const prefix = "urn:pulumi:dev::app::"
+
+var resources = []apitype.ResourceV3{
+	{URN: prefix + "pulumi:pulumi:Stack::app-dev", Type: "pulumi:pulumi:Stack"},
+	{URN: prefix + "sst:aws:Bucket::Uploads", Type: "sst:aws:Bucket", Parent: prefix + "pulumi:pulumi:Stack::app-dev"},
+	{URN: prefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::UploadsBucket", Type: "aws:s3/bucketV2:BucketV2", Parent: prefix + "sst:aws:Bucket::Uploads"},
+	{URN: prefix + "sst:aws:Function::Api", Type: "sst:aws:Function", Parent: prefix + "pulumi:pulumi:Stack::app-dev", Outputs: map[string]interface{}{
+		"_metadata": map[string]interface{}{"links": []interface{}{"Uploads"}},
+	}},
+	{
+		URN:    prefix + "sst:aws:Function$aws:lambda/function:Function::ApiFunction",
+		Type:   "aws:lambda/function:Function",
+		Parent: prefix + "sst:aws:Function::Api",
+		PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+			"environment": {prefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::UploadsBucket"},
+		},
+	},
+	{URN: prefix + "sst:sst:LinkRef::UploadsLinkRef", Type: "sst:sst:LinkRef", Parent: prefix + "pulumi:pulumi:Stack::app-dev"},
+}
+
+func TestGraph(t *testing.T) {
+	g := graph.New(resources)
+	if len(g.Nodes) != 4 {
+		t.Fatalf("expected 4 nodes, got %v", g.Nodes)
+	}
+	if len(g.Edges) != 3 {
+		t.Fatalf("expected 2 parent edges and 1 property edge, got %v", g.Edges)
+	}
+
+	collapsed := graph.New(resources).Collapse()
+	if len(collapsed.Nodes) != 2 || len(collapsed.Edges) != 1 {
+		t.Fatalf("unexpected collapsed graph %v", collapsed)
+	}
+	edge := collapsed.Edges[0]
+	if edge.From != prefix+"sst:aws:Function::Api" || edge.To != prefix+"sst:aws:Bucket::Uploads" || edge.Kind != graph.EdgeDependency {
+		t.Errorf("unexpected edge %v", edge)
+	}
+
+	root := graph.New(resources).Root("Api")
+	if len(root.Nodes) != 2 || len(root.Edges) != 1 {
+		t.Fatalf("unexpected root graph %v", root)
+	}
+
+	types := graph.New(resources).Types([]string{"aws:*"})
+	if len(types.Nodes) != 2 || len(types.Edges) != 1 || types.Edges[0].Property != "environment" {
+		t.Fatalf("unexpected types graph %v", types)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	g := graph.Links(resources, []string{"Uploads"})
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 {
+		t.Fatalf("unexpected links graph %v", g)
+	}
+	if g.Edges[0].Kind != graph.EdgeLink || g.Edges[0].To != prefix+"sst:aws:Bucket::Uploads" {
+		t.Errorf("unexpected edge %v", g.Edges[0])
+	}
+}
@@ -0,0 +1,90 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// these change between runs without changing what gets deployed
+var volatileEnv = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"SST_RUN_ID",
+	"SST_LOG",
+	"SST_LOG_CHILDREN",
+	"SST_PRINT_LOGS",
+	"SST_VERBOSE",
+	"SST_NO_CLEANUP",
+	"SST_NO_CONFIG_CACHE",
+	"SST_TELEMETRY_DISABLED",
+}
+
+// inputHash hashes everything that goes into an update: the files that make up
+// the config, the values it's evaluated with, the provider lock, the provider
+// environment along with any SST_ variables, the secrets and the CLI version.
+// If none of these changed since the last successful deploy there is nothing
+// to deploy.
+func (p *Project) inputHash(files []string, defines map[string]string, secrets ...map[string]string) (string, error) {
+	configHash, err := hashFiles(p.PathRoot(), files)
+	if err != nil {
+		return "", err
+	}
+	env := map[string]string{}
+	for key, value := range p.Env() {
+		env[key] = value
+	}
+	for _, item := range os.Environ() {
+		key, value, _ := strings.Cut(item, "=")
+		if strings.HasPrefix(key, "SST_") {
+			env[key] = value
+		}
+	}
+	for _, key := range volatileEnv {
+		delete(env, key)
+	}
+	hash := sha256.New()
+	write := func(label string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		hash.Write([]byte(label))
+		hash.Write(data)
+		return nil
+	}
+	if err := write("version", p.Version()); err != nil {
+		return "", err
+	}
+	if err := write("config", configHash); err != nil {
+		return "", err
+	}
+	if err := write("defines", sortedPairs(defines)); err != nil {
+		return "", err
+	}
+	if err := write("lock", p.lock); err != nil {
+		return "", err
+	}
+	if err := write("env", sortedPairs(env)); err != nil {
+		return "", err
+	}
+	for _, item := range secrets {
+		if err := write("secrets", sortedPairs(item)); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func sortedPairs(input map[string]string) []string {
+	result := []string{}
+	for key, value := range input {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,81 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ImportFix is what needs to be set on a resource for its import to match the
+// live resource. Args holds the live values, with nil for the ones that need
+// to be unset.
+type ImportFix struct {
+	URN       string                 `json:"urn"`
+	Name      string                 `json:"name"`
+	Type      string                 `json:"type"`
+	Transform bool                   `json:"transform"`
+	Args      map[string]interface{} `json:"args"`
+	Snippet   string                 `json:"snippet"`
+}
+
+// NewImportFix builds the fix for the import diffs of a resource. Resources
+// created by SST components need to be fixed in the component's transform,
+// the rest in their args.
+func NewImportFix(urn string, diffs []ImportDiff) ImportFix {
+	parsed := resource.URN(urn)
+	fix := ImportFix{
+		URN:       urn,
+		Name:      parsed.Name(),
+		Type:      string(parsed.Type()),
+		Transform: strings.Contains(urn, "::sst"),
+		Args:      map[string]interface{}{},
+	}
+	sorted := append([]ImportDiff{}, diffs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Input < sorted[j].Input
+	})
+	lines := []string{}
+	for _, diff := range sorted {
+		fix.Args[diff.Input] = diff.Old
+		value := "undefined"
+		if diff.Old != nil {
+			data, _ := json.Marshal(diff.Old)
+			value = string(data)
+		}
+		if fix.Transform {
+			lines = append(lines, "args."+diff.Input+" = "+value+";")
+			continue
+		}
+		lines = append(lines, diff.Input+": "+value+",")
+	}
+	fix.Snippet = strings.Join(lines, "\n")
+	return fix
+}
+
+// WriteImportFixes writes the fixes for every failed import so they can be
+// applied without going through the output. The file is removed when there
+// are none so it never describes an older deploy.
+func WriteImportFixes(path string, diffs map[string][]ImportDiff) error {
+	if len(diffs) == 0 {
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	fixes := []ImportFix{}
+	for urn, items := range diffs {
+		fixes = append(fixes, NewImportFix(urn, items))
+	}
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].URN < fixes[j].URN
+	})
+	data, err := json.MarshalIndent(fixes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,148 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/internal/util"
+)
+
+// ImportEntry is an existing resource to adopt. Name and Type are the name and
+// type of the resource in the app, like `MyBucketBucket` and
+// `aws:s3/bucketV2:BucketV2`, and ID is the physical ID of the live resource.
+type ImportEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// ImportManifest uses the same format as a pulumi import file.
+type ImportManifest struct {
+	Resources []ImportEntry `json:"resources"`
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusImported ImportStatus = "imported"
+	ImportStatusMismatch ImportStatus = "mismatch"
+	ImportStatusFailed   ImportStatus = "failed"
+	ImportStatusNotFound ImportStatus = "not found"
+	ImportStatusManaged  ImportStatus = "already managed"
+)
+
+type ImportResult struct {
+	ImportEntry
+	URN     string       `json:"urn,omitempty"`
+	Status  ImportStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+	Snippet string       `json:"snippet,omitempty"`
+}
+
+// ImportReportEvent is published after `sst import` with how each resource in
+// the manifest went.
+type ImportReportEvent struct {
+	Results []ImportResult `json:"results"`
+}
+
+func LoadImportManifest(path string) ([]ImportEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not read manifest "+path)
+	}
+	manifest := ImportManifest{}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not parse manifest "+path)
+	}
+	if len(manifest.Resources) == 0 {
+		return nil, util.NewReadableError(nil, "There are no resources in "+path)
+	}
+	seen := map[string]bool{}
+	for i, entry := range manifest.Resources {
+		if entry.Name == "" || entry.Type == "" || entry.ID == "" {
+			return nil, util.NewReadableError(nil, fmt.Sprintf("Resource %v in %v needs a name, type and id", i+1, path))
+		}
+		key := entry.Type + "::" + entry.Name
+		if seen[key] {
+			return nil, util.NewReadableError(nil, fmt.Sprintf("%v is listed more than once in %v", entry.Name, path))
+		}
+		seen[key] = true
+	}
+	return manifest.Resources, nil
+}
+
+// NewImportReport matches the entries of the manifest to the resources of the
+// deploy that imported them. Resources that imported with different args get
+// the snippet that fixes them, the rest get the snippet to commit so they
+// keep being imported. Entries that were in the state before the deploy are
+// left alone by pulumi, so they are reported as already managed.
+func NewImportReport(entries []ImportEntry, before []apitype.ResourceV3, resources []apitype.ResourceV3, errors []Error, diffs map[string][]ImportDiff) *ImportReportEvent {
+	find := func(entry ImportEntry, urn string) bool {
+		parsed := resource.URN(urn)
+		return parsed.IsValid() && parsed.Name() == entry.Name && string(parsed.Type()) == entry.Type
+	}
+	result := &ImportReportEvent{Results: []ImportResult{}}
+	for _, entry := range entries {
+		item := ImportResult{
+			ImportEntry: entry,
+			Status:      ImportStatusNotFound,
+			Message:     "no resource with this name and type in the app",
+		}
+		for _, res := range before {
+			if find(entry, string(res.URN)) {
+				item.URN = string(res.URN)
+				item.Status = ImportStatusManaged
+				item.Message = "it was already in the state, nothing was imported"
+			}
+		}
+		if item.Status == ImportStatusManaged {
+			result.Results = append(result.Results, item)
+			continue
+		}
+		for urn, items := range diffs {
+			if find(entry, urn) {
+				item.URN = urn
+				item.Status = ImportStatusMismatch
+				item.Message = fmt.Sprintf("%v args don't match the live resource", len(items))
+				item.Snippet = NewImportFix(urn, items).Snippet
+			}
+		}
+		if item.Status == ImportStatusNotFound {
+			for _, err := range errors {
+				if find(entry, err.URN) {
+					item.URN = err.URN
+					item.Status = ImportStatusFailed
+					item.Message = err.Message
+				}
+			}
+		}
+		if item.Status == ImportStatusNotFound {
+			for _, res := range resources {
+				if find(entry, string(res.URN)) {
+					item.URN = string(res.URN)
+					item.Status = ImportStatusImported
+					item.Message = ""
+					item.Snippet = importSnippet(item.URN, entry.ID)
+				}
+			}
+		}
+		result.Results = append(result.Results, item)
+	}
+	return result
+}
+
+// importSnippet is what to add to the config so the resource stays imported.
+// Resources created by SST components are imported in the component's
+// transform, like the fixes for a failed import.
+func importSnippet(urn string, id string) string {
+	data, _ := json.Marshal(id)
+	if strings.Contains(urn, "::sst") {
+		return "opts.import = " + string(data) + ";"
+	}
+	return "import: " + string(data) + ","
+}
@@ -0,0 +1,65 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestNewImportReport(t *testing.T) {
+	const prefix = "urn:pulumi:dev::app::"
+	entries := []project.ImportEntry{
+		{Name: "UploadsBucket", Type: "aws:s3/bucketV2:BucketV2", ID: "uploads"},
+		{Name: "Logs", Type: "aws:cloudwatch/logGroup:LogGroup", ID: "/logs"},
+		{Name: "Table", Type: "aws:dynamodb/table:Table", ID: "table"},
+		{Name: "Queue", Type: "aws:sqs/queue:Queue", ID: "queue"},
+		{Name: "Topic", Type: "aws:sns/topic:Topic", ID: "topic"},
+	}
+	before := []apitype.ResourceV3{
+		{
+			URN:  prefix + "aws:sns/topic:Topic::Topic",
+			Type: "aws:sns/topic:Topic",
+			ID:   "topic",
+		},
+	}
+	resources := []apitype.ResourceV3{
+		{
+			URN:  prefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::UploadsBucket",
+			Type: "aws:s3/bucketV2:BucketV2",
+			ID:   "uploads",
+		},
+		before[0],
+	}
+	errors := []project.Error{
+		{URN: prefix + "aws:dynamodb/table:Table::Table", Message: "table not found"},
+	}
+	diffs := map[string][]project.ImportDiff{
+		prefix + "aws:cloudwatch/logGroup:LogGroup::Logs": {
+			{URN: prefix + "aws:cloudwatch/logGroup:LogGroup::Logs", Input: "retentionInDays", Old: float64(7)},
+		},
+	}
+
+	report := project.NewImportReport(entries, before, resources, errors, diffs)
+	expected := []project.ImportStatus{
+		project.ImportStatusImported,
+		project.ImportStatusMismatch,
+		project.ImportStatusFailed,
+		project.ImportStatusNotFound,
+		project.ImportStatusManaged,
+	}
+	for i, result := range report.Results {
+		if result.Status != expected[i] {
+			t.Errorf("expected %v to be %v, got %v", result.Name, expected[i], result.Status)
+		}
+	}
+	if report.Results[0].Snippet != `opts.import = "uploads";` {
+		t.Errorf("unexpected snippet %q", report.Results[0].Snippet)
+	}
+	if report.Results[1].Snippet != "retentionInDays: 7," {
+		t.Errorf("unexpected snippet %q", report.Results[1].Snippet)
+	}
+	if report.Results[4].Snippet != "" {
+		t.Errorf("expected no snippet for a managed resource, got %q", report.Results[4].Snippet)
+	}
+}
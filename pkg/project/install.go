@@ -194,6 +194,12 @@ type ProviderLockEntry struct {
 
 type ProviderLock = []*ProviderLockEntry
 
+// ProviderLock returns the providers that are installed, with the versions
+// they resolved to.
+func (p *Project) ProviderLock() ProviderLock {
+	return p.lock
+}
+
 func (p *Project) loadProviderLock() error {
 	lockPath := path.ResolveProviderLock(p.PathConfig())
 	data, err := os.ReadFile(lockPath)
@@ -0,0 +1,137 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/pkg/id"
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+// MaskedValue is shown in place of anything that is a secret in the state.
+const MaskedValue = "[secret]"
+
+// InventoryResource is a resource in the state of a stage, flattened so it
+// can be exported.
+type InventoryResource struct {
+	Stage    string     `json:"stage"`
+	URN      string     `json:"urn"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Provider string     `json:"provider,omitempty"`
+	ID       string     `json:"id,omitempty"`
+	Region   string     `json:"region,omitempty"`
+	Account  string     `json:"account,omitempty"`
+	Parent   string     `json:"parent,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// Inventory lists the resources in the state of a stage. The state is not
+// decrypted, so secrets never leave the home.
+func (p *Project) Inventory(stage string) ([]InventoryResource, error) {
+	workdir, err := p.NewWorkdir(id.Descending())
+	if err != nil {
+		return nil, err
+	}
+	defer workdir.Cleanup()
+	path := filepath.Join(workdir.path, "inventory", stage+".json")
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	err = provider.PullState(p.home, p.app.Name, stage, path)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := readCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewInventory(stage, checkpoint), nil
+}
+
+// NewInventory flattens the resources in a checkpoint. The region and account
+// come from the provider inputs, falling back to the resource itself.
+func NewInventory(stage string, checkpoint *apitype.CheckpointV3) []InventoryResource {
+	result := []InventoryResource{}
+	if checkpoint.Latest == nil {
+		return result
+	}
+	providers := map[string]apitype.ResourceV3{}
+	for _, res := range checkpoint.Latest.Resources {
+		if strings.HasPrefix(string(res.Type), "pulumi:providers:") {
+			providers[string(res.URN)] = res
+		}
+	}
+	for _, res := range checkpoint.Latest.Resources {
+		item := InventoryResource{
+			Stage:    stage,
+			URN:      string(res.URN),
+			Name:     res.URN.Name(),
+			Type:     string(res.Type),
+			ID:       string(res.ID),
+			Parent:   string(res.Parent),
+			Created:  res.Created,
+			Modified: res.Modified,
+		}
+		var inputs map[string]interface{}
+		if res.Provider != "" {
+			// provider references are the provider urn followed by its id
+			urn := res.Provider
+			if index := strings.LastIndex(urn, "::"); index != -1 {
+				urn = urn[:index]
+			}
+			item.Provider = strings.TrimPrefix(string(resource.URN(urn).Type()), "pulumi:providers:")
+			inputs = providers[urn].Inputs
+		}
+		item.Region = inventoryValue(inputs, "region")
+		if item.Region == "" {
+			item.Region = inventoryValue(res.Outputs, "region")
+		}
+		item.Account = inventoryValue(res.Inputs, "accountId")
+		if item.Account == "" {
+			item.Account = arnAccount(inventoryValue(res.Outputs, "arn"))
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func inventoryValue(values map[string]interface{}, key string) string {
+	value, ok := values[key]
+	if !ok {
+		return ""
+	}
+	if isSecret(value) {
+		return MaskedValue
+	}
+	if cast, ok := value.(string); ok {
+		return cast
+	}
+	return ""
+}
+
+// isSecret checks for the signature pulumi wraps secret values with
+func isSecret(value interface{}) bool {
+	cast, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	return cast[resource.SigKey] == resource.SecretSig
+}
+
+func arnAccount(arn string) string {
+	if arn == MaskedValue {
+		return arn
+	}
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[4]
+}
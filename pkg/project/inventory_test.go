@@ -0,0 +1,67 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestNewInventory(t *testing.T) {
+	const prefix = "urn:pulumi:dev::app::"
+	checkpoint := &apitype.CheckpointV3{
+		Latest: &apitype.DeploymentV3{
+			Resources: []apitype.ResourceV3{
+				{
+					URN:    prefix + "pulumi:providers:aws::default_6_66_2",
+					Type:   "pulumi:providers:aws",
+					ID:     "0d9e0e6d",
+					Inputs: map[string]interface{}{"region": "us-east-1"},
+				},
+				{
+					URN:      prefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::UploadsBucket",
+					Type:     "aws:s3/bucketV2:BucketV2",
+					ID:       "app-dev-uploadsbucket",
+					Parent:   prefix + "sst:aws:Bucket::Uploads",
+					Provider: prefix + "pulumi:providers:aws::default_6_66_2::0d9e0e6d",
+					Outputs:  map[string]interface{}{"arn": "arn:aws:s3:::app-dev-uploadsbucket"},
+				},
+				{
+					URN:      prefix + "sst:aws:Function$aws:lambda/function:Function::ApiFunction",
+					Type:     "aws:lambda/function:Function",
+					ID:       "app-dev-api",
+					Provider: prefix + "pulumi:providers:aws::default_6_66_2::0d9e0e6d",
+					Outputs: map[string]interface{}{
+						"arn": map[string]interface{}{
+							"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270",
+							"ciphertext":                       "v1:abc",
+						},
+					},
+				},
+				{
+					URN:      prefix + "sst:cloudflare:Worker$cloudflare:index/workerScript:WorkerScript::Web",
+					Type:     "cloudflare:index/workerScript:WorkerScript",
+					Provider: prefix + "pulumi:providers:cloudflare::default::4f1c",
+					Inputs:   map[string]interface{}{"accountId": "24c8a1"},
+				},
+			},
+		},
+	}
+	result := project.NewInventory("dev", checkpoint)
+	if len(result) != 4 {
+		t.Fatalf("expected 4 resources, got %v", len(result))
+	}
+
+	bucket := result[1]
+	if bucket.Provider != "aws" || bucket.Region != "us-east-1" || bucket.Account != "" || bucket.Name != "UploadsBucket" {
+		t.Errorf("unexpected bucket %+v", bucket)
+	}
+	function := result[2]
+	if function.Account != project.MaskedValue {
+		t.Errorf("expected the secret arn to be masked, got %v", function.Account)
+	}
+	worker := result[3]
+	if worker.Provider != "cloudflare" || worker.Account != "24c8a1" || worker.Region != "" {
+		t.Errorf("unexpected worker %+v", worker)
+	}
+}
@@ -0,0 +1,152 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// Plan is the output of `sst diff --save-plan`. It records the changes that
+// were reviewed along with hashes of the config and state they were computed
+// from, so `sst deploy --plan` can refuse to apply anything else.
+type Plan struct {
+	Version    string          `json:"version"`
+	App        string          `json:"app"`
+	Stage      string          `json:"stage"`
+	ConfigHash string          `json:"configHash"`
+	StateHash  string          `json:"stateHash"`
+	Steps      []PlanStep      `json:"steps"`
+	Pulumi     json.RawMessage `json:"pulumi,omitempty"`
+}
+
+type PlanStep struct {
+	URN   string         `json:"urn"`
+	Type  string         `json:"type"`
+	Op    apitype.OpType `json:"op"`
+	Diffs []string       `json:"diffs,omitempty"`
+}
+
+type ErrPlanChanged struct {
+	Reasons []string
+}
+
+func (err *ErrPlanChanged) Error() string {
+	return "plan changed: " + strings.Join(err.Reasons, ", ")
+}
+
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan Plan
+	err = json.Unmarshal(data, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan file %v: %w", path, err)
+	}
+	return &plan, nil
+}
+
+func (plan *Plan) Write(path string) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Compare checks the plan against the config, state and previewed steps of the
+// update that is about to run and returns an *ErrPlanChanged if they differ.
+func (plan *Plan) Compare(app, stage, configHash, stateHash string, steps []apitype.StepEventMetadata) error {
+	reasons := []string{}
+	if plan.App != app || plan.Stage != stage {
+		reasons = append(reasons, fmt.Sprintf("plan is for %v/%v", plan.App, plan.Stage))
+	}
+	if plan.ConfigHash != configHash {
+		reasons = append(reasons, "config changed")
+	}
+	if plan.StateHash != stateHash {
+		reasons = append(reasons, "state changed")
+	}
+	expected := map[string]PlanStep{}
+	for _, step := range plan.Steps {
+		expected[string(step.Op)+step.URN] = step
+	}
+	actual := planSteps(steps)
+	for _, step := range actual {
+		key := string(step.Op) + step.URN
+		match, ok := expected[key]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("unexpected %v of %v", step.Op, step.URN))
+			continue
+		}
+		delete(expected, key)
+		if !slices.Equal(match.Diffs, step.Diffs) {
+			reasons = append(reasons, fmt.Sprintf("%v changes %v instead of %v", step.URN, strings.Join(step.Diffs, ", "), strings.Join(match.Diffs, ", ")))
+		}
+	}
+	for _, step := range plan.Steps {
+		if _, ok := expected[string(step.Op)+step.URN]; ok {
+			reasons = append(reasons, fmt.Sprintf("missing %v of %v", step.Op, step.URN))
+		}
+	}
+	if len(reasons) > 0 {
+		return &ErrPlanChanged{Reasons: reasons}
+	}
+	return nil
+}
+
+func planSteps(steps []apitype.StepEventMetadata) []PlanStep {
+	result := []PlanStep{}
+	for _, step := range steps {
+		if step.Op == apitype.OpSame {
+			continue
+		}
+		diffs := slices.Clone(step.Diffs)
+		sort.Strings(diffs)
+		result = append(result, PlanStep{
+			URN:   step.URN,
+			Type:  step.Type,
+			Op:    step.Op,
+			Diffs: diffs,
+		})
+	}
+	return result
+}
+
+// hashFiles hashes the path and contents of each file. Paths are made relative
+// to root so the hash is the same across machines.
+func hashFiles(root string, files []string) (string, error) {
+	sorted := slices.Clone(files)
+	sort.Strings(sorted)
+	hash := sha256.New()
+	for _, path := range sorted {
+		file, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		hash.Write([]byte(filepath.ToSlash(rel)))
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,233 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/yalp/jsonpath"
+)
+
+const FILE = "sst.policy.json"
+
+type Severity string
+
+const (
+	SeverityAdvisory  Severity = "advisory"
+	SeverityMandatory Severity = "mandatory"
+)
+
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule matches planned steps by stage, resource type, name and operation. A
+// step that matches all of the selectors and all of the input conditions is a
+// violation. Empty selectors match everything, except that `same` steps are
+// only checked when listed in Ops.
+type Rule struct {
+	ID          string           `json:"id"`
+	Description string           `json:"description"`
+	Severity    Severity         `json:"severity"`
+	Stages      []string         `json:"stages"`
+	Types       []string         `json:"types"`
+	Names       []string         `json:"names"`
+	Ops         []apitype.OpType `json:"ops"`
+	Inputs      []Condition      `json:"inputs"`
+}
+
+// Condition checks a property of the step's inputs. The path is a jsonpath
+// relative to the inputs, like `acl` or `tags.env`.
+type Condition struct {
+	Path      string        `json:"path"`
+	Equals    interface{}   `json:"equals,omitempty"`
+	NotEquals interface{}   `json:"notEquals,omitempty"`
+	In        []interface{} `json:"in,omitempty"`
+	Matches   string        `json:"matches,omitempty"`
+	Exists    *bool         `json:"exists,omitempty"`
+}
+
+type Violation struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	URN      string   `json:"urn"`
+	Op       string   `json:"op"`
+}
+
+// Load reads the policy file from the root of the app. A missing file is an
+// empty policy.
+func Load(root string) (*Policy, error) {
+	result := &Policy{
+		Rules: []Rule{},
+	}
+	data, err := os.ReadFile(filepath.Join(root, FILE))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", FILE, err)
+	}
+	for index, rule := range result.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("%v: rule %v is missing an id", FILE, index)
+		}
+		if rule.Severity == "" {
+			result.Rules[index].Severity = SeverityAdvisory
+		}
+		if rule.Severity != "" && rule.Severity != SeverityAdvisory && rule.Severity != SeverityMandatory {
+			return nil, fmt.Errorf("%v: rule %v has invalid severity %q", FILE, rule.ID, rule.Severity)
+		}
+		for _, condition := range rule.Inputs {
+			if condition.Matches == "" {
+				continue
+			}
+			if _, err := regexp.Compile(condition.Matches); err != nil {
+				return nil, fmt.Errorf("%v: rule %v: %w", FILE, rule.ID, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (p *Policy) Evaluate(stage string, steps []apitype.StepEventMetadata) []Violation {
+	result := []Violation{}
+	seen := map[string]bool{}
+	for _, rule := range p.Rules {
+		if len(rule.Stages) > 0 && !slices.Contains(rule.Stages, stage) {
+			continue
+		}
+		for _, step := range steps {
+			if !rule.match(step) {
+				continue
+			}
+			key := rule.ID + step.URN
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			message := rule.Description
+			if message == "" {
+				message = rule.ID
+			}
+			result = append(result, Violation{
+				Rule:     rule.ID,
+				Severity: rule.Severity,
+				Message:  message,
+				URN:      step.URN,
+				Op:       string(step.Op),
+			})
+		}
+	}
+	return result
+}
+
+func Mandatory(violations []Violation) bool {
+	for _, violation := range violations {
+		if violation.Severity == SeverityMandatory {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) match(step apitype.StepEventMetadata) bool {
+	if !matchOp(r.Ops, step.Op) {
+		return false
+	}
+	if len(r.Types) > 0 && !matchGlob(r.Types, step.Type) {
+		return false
+	}
+	if len(r.Names) > 0 && !matchGlob(r.Names, resource.URN(step.URN).Name()) {
+		return false
+	}
+	inputs := map[string]interface{}{}
+	if step.Old != nil && step.Old.Inputs != nil {
+		inputs = step.Old.Inputs
+	}
+	if step.New != nil && step.New.Inputs != nil {
+		inputs = step.New.Inputs
+	}
+	for _, condition := range r.Inputs {
+		if !condition.match(inputs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Condition) match(inputs map[string]interface{}) bool {
+	value, err := jsonpath.Read(inputs, "$."+c.Path)
+	exists := err == nil && value != nil
+	if c.Exists != nil && *c.Exists != exists {
+		return false
+	}
+	if c.Equals != nil && (!exists || !equal(value, c.Equals)) {
+		return false
+	}
+	if c.NotEquals != nil && exists && equal(value, c.NotEquals) {
+		return false
+	}
+	if len(c.In) > 0 {
+		if !exists || !slices.ContainsFunc(c.In, func(item interface{}) bool { return equal(value, item) }) {
+			return false
+		}
+	}
+	if c.Matches != "" {
+		str, ok := value.(string)
+		if !ok || !regexp.MustCompile(c.Matches).MatchString(str) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchOp(ops []apitype.OpType, op apitype.OpType) bool {
+	if len(ops) == 0 {
+		return op != apitype.OpSame
+	}
+	for _, item := range ops {
+		if item == op {
+			return true
+		}
+		if item == apitype.OpReplace && (op == apitype.OpCreateReplacement || op == apitype.OpDeleteReplaced) {
+			return true
+		}
+	}
+	return false
+}
+
+// patterns only support `*` as a wildcard, which also matches `/` and `:` so
+// `aws:s3/*` matches every s3 resource
+func matchGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if regexp.MustCompile(expr).MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// values come from json so numbers are float64, normalize the expected value
+// the same way before comparing
+func equal(value interface{}, expected interface{}) bool {
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var normalized interface{}
+	json.Unmarshal(data, &normalized)
+	return reflect.DeepEqual(value, normalized)
+}
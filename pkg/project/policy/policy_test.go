@@ -0,0 +1,115 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/pkg/project/common"
+	"github.com/sst/sst/v3/pkg/project/policy"
+)
+
+func steps(t *testing.T) []apitype.StepEventMetadata {
+	file, err := os.Open(filepath.Join("testdata", "eventlog.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	result, err := common.ReadSteps(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func load(t *testing.T, content string) *policy.Policy {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, policy.FILE), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := policy.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestEvaluate(t *testing.T) {
+	p := load(t, `{
+  "rules": [
+    {
+      "id": "no-db-replace",
+      "severity": "mandatory",
+      "stages": ["production"],
+      "types": ["aws:rds/cluster:Cluster"],
+      "ops": ["replace", "delete"]
+    },
+    {
+      "id": "no-public-buckets",
+      "types": ["aws:s3/*"],
+      "inputs": [{ "path": "acl", "in": ["public-read", "public-read-write"] }]
+    },
+    {
+      "id": "max-memory",
+      "names": ["Api*"],
+      "inputs": [{ "path": "memorySize", "equals": 3008 }]
+    },
+    {
+      "id": "tagged",
+      "types": ["aws:s3/*"],
+      "inputs": [{ "path": "tags.env", "exists": false }]
+    }
+  ]
+}`)
+	violations := p.Evaluate("production", steps(t))
+	expected := map[string]policy.Severity{
+		"no-db-replace":     policy.SeverityMandatory,
+		"no-public-buckets": policy.SeverityAdvisory,
+		"max-memory":        policy.SeverityAdvisory,
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %v violations, got %v: %+v", len(expected), len(violations), violations)
+	}
+	for _, violation := range violations {
+		severity, ok := expected[violation.Rule]
+		if !ok {
+			t.Errorf("unexpected violation %v on %v", violation.Rule, violation.URN)
+			continue
+		}
+		if severity != violation.Severity {
+			t.Errorf("expected %v to be %v, got %v", violation.Rule, severity, violation.Severity)
+		}
+	}
+	if !policy.Mandatory(violations) {
+		t.Errorf("expected a mandatory violation")
+	}
+}
+
+func TestEvaluateStage(t *testing.T) {
+	p := load(t, `{ "rules": [{ "id": "no-db-replace", "severity": "mandatory", "stages": ["production"], "ops": ["replace"] }] }`)
+	violations := p.Evaluate("dev", steps(t))
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations, got %+v", violations)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	p, err := policy.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 0 {
+		t.Fatalf("expected no rules, got %v", len(p.Rules))
+	}
+}
+
+func TestLoadInvalidSeverity(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, policy.FILE), []byte(`{ "rules": [{ "id": "a", "severity": "fatal" }] }`), 0644)
+	_, err := policy.Load(dir)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
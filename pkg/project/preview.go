@@ -0,0 +1,63 @@
+package project
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/process"
+	"github.com/sst/sst/v3/pkg/project/common"
+)
+
+// preview runs a pulumi preview with the same arguments as the update that is
+// about to run and returns the steps it planned. This lets us check the
+// changes before anything is applied.
+func (p *Project) preview(ctx context.Context, workdir *PulumiWorkdir, pulumiPath string, args []string, env []string, stdout io.Writer, stderr io.Writer) ([]apitype.StepEventMetadata, error) {
+	steps, err := p.previewSteps(ctx, workdir, pulumiPath, []string{"preview"}, args, env, stdout, stderr)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not preview the changes before deploying. Run `sst diff` to see the errors.")
+	}
+	return steps, nil
+}
+
+// previewDestroy runs a destroy without applying it and returns the deletes it
+// planned, including the ones that would only be dropped from the state.
+func (p *Project) previewDestroy(ctx context.Context, workdir *PulumiWorkdir, pulumiPath string, args []string, env []string, stdout io.Writer, stderr io.Writer) ([]apitype.StepEventMetadata, error) {
+	steps, err := p.previewSteps(ctx, workdir, pulumiPath, []string{"destroy", "--preview-only"}, args, env, stdout, stderr)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not preview the remove. Check the logs in .sst/log/pulumi.err.log for the errors.")
+	}
+	return steps, nil
+}
+
+func (p *Project) previewSteps(ctx context.Context, workdir *PulumiWorkdir, pulumiPath string, command []string, args []string, env []string, stdout io.Writer, stderr io.Writer) ([]apitype.StepEventMetadata, error) {
+	log := slog.Default().With("service", "project.preview")
+	eventlogPath := filepath.Join(workdir.path, "preview.eventlog.json")
+	cmd := process.CommandContext(ctx, pulumiPath, append(append(slices.Clone(command), "--event-log", eventlogPath), args...)...)
+	process.Detach(cmd)
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Dir = workdir.Backend()
+	log.Info("starting pulumi preview", "args", cmd.Args)
+	err := cmd.Run()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(eventlogPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	steps, err := common.ReadSteps(file)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("previewed steps", "count", len(steps))
+	return steps, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/sst/sst/v3/internal/fs"
 	"github.com/sst/sst/v3/internal/util"
 	"github.com/sst/sst/v3/pkg/flag"
+	"github.com/sst/sst/v3/pkg/freeze"
 	"github.com/sst/sst/v3/pkg/js"
 	"github.com/sst/sst/v3/pkg/process"
 	"github.com/sst/sst/v3/pkg/project/provider"
@@ -28,14 +29,17 @@ import (
 )
 
 type App struct {
-	Name      string                 `json:"name"`
-	Stage     string                 `json:"stage"`
-	Removal   string                 `json:"removal"`
-	Providers map[string]interface{} `json:"providers"`
-	Home      string                 `json:"home"`
-	Version   string                 `json:"version"`
-	Protect   bool                   `json:"protect"`
-	Watch     []string               `json:"watch"`
+	Name       string                 `json:"name"`
+	Stage      string                 `json:"stage"`
+	Removal    string                 `json:"removal"`
+	Providers  map[string]interface{} `json:"providers"`
+	Home       string                 `json:"home"`
+	Version    string                 `json:"version"`
+	Protect    bool                   `json:"protect"`
+	Protected  []string               `json:"protected"`
+	Freeze     []freeze.Window        `json:"freeze"`
+	Watch      []string               `json:"watch"`
+	References map[string]Reference   `json:"references"`
 	// Deprecated: Backend is now Home
 	Backend string `json:"backend"`
 	// Deprecated: RemovalPolicy is now Removal
@@ -47,6 +51,7 @@ type Project struct {
 	lock            ProviderLock
 	root            string
 	config          string
+	branch          string
 	app             *App
 	home            provider.Home
 	env             map[string]string
@@ -86,6 +91,8 @@ type ProjectConfig struct {
 	Version string
 	Stage   string
 	Config  string
+	// Branch is the git branch the stage was derived from
+	Branch string
 }
 
 var ErrInvalidStageName = fmt.Errorf("ErrInvalidStageName")
@@ -93,6 +100,7 @@ var ErrInvalidAppName = fmt.Errorf("ErrInvalidAppName")
 var ErrAppNameChanged = fmt.Errorf("ErrAppNameChanged")
 var ErrV2Config = fmt.Errorf("ErrV2Config")
 var ErrVersionInvalid = fmt.Errorf("ErrVersionInvalid")
+var ErrConfigNotCached = fmt.Errorf("ErrConfigNotCached")
 
 type ErrVersionMismatch struct {
 	Needed   string
@@ -116,6 +124,73 @@ var InvalidStageRegex = regexp.MustCompile(`[^a-zA-Z0-9-]`)
 var InvalidAppRegex = regexp.MustCompile(`^[^a-zA-Z]|[^a-zA-Z0-9-]`)
 
 func New(input *ProjectConfig) (*Project, error) {
+	proj, err := newProject(input)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := proj.readConfigCache(input)
+	if ok {
+		slog.Info("using cached config")
+	} else {
+		data, err = proj.evaluate(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if data != nil {
+		err = proj.parseApp(data, input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = proj.loadProviderLock()
+	if err != nil {
+		return nil, err
+	}
+
+	return proj, nil
+}
+
+// NewCached loads the project from the last config that was evaluated for the
+// stage, even if the config has changed since, so the config isn't built or
+// run. It's only for commands that read from the home. The app name and home
+// can be passed in to use instead, or when the config was never evaluated.
+func NewCached(input *ProjectConfig, app, home string) (*Project, error) {
+	proj, err := newProject(input)
+	if err != nil {
+		return nil, err
+	}
+	parsed := map[string]interface{}{}
+	if data, ok := proj.readConfigCacheAny(input.Stage); ok {
+		err = json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if app != "" {
+		parsed["name"] = app
+	}
+	if home != "" && home != parsed["home"] {
+		parsed["home"] = home
+		delete(parsed, "providers")
+	}
+	if parsed["name"] == nil || parsed["home"] == nil {
+		return nil, ErrConfigNotCached
+	}
+	data, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	err = proj.parseApp(data, input)
+	if err != nil {
+		return nil, err
+	}
+	return proj, nil
+}
+
+func newProject(input *ProjectConfig) (*Project, error) {
 	if InvalidStageRegex.MatchString(input.Stage) {
 		return nil, ErrInvalidStageName
 	}
@@ -126,6 +201,7 @@ func New(input *ProjectConfig) (*Project, error) {
 		version: input.Version,
 		root:    rootPath,
 		config:  input.Config,
+		branch:  input.Branch,
 		env:     map[string]string{},
 		Runtime: runtime.NewCollection(
 			input.Config,
@@ -148,7 +224,12 @@ func New(input *ProjectConfig) (*Project, error) {
 			return nil, err
 		}
 	}
+	return proj, nil
+}
 
+// evaluate runs the app function in the config for the stage and returns what
+// it returns as JSON.
+func (proj *Project) evaluate(input *ProjectConfig) ([]byte, error) {
 	inputBytes, err := json.Marshal(map[string]string{
 		"stage": input.Stage,
 	})
@@ -193,101 +274,104 @@ console.log("~j" + JSON.stringify(await mod.app({
 			return nil, ErrV2Config
 		}
 		if strings.HasPrefix(line, "~j") {
-			var parsed App
-			err = json.Unmarshal([]byte(line[2:]), &parsed)
+			data := []byte(line[2:])
+			err = proj.writeConfigCache(input, buildResult.Metafile, data)
 			if err != nil {
-				return nil, err
+				slog.Error("failed to cache config", "err", err)
 			}
-			proj.app = &parsed
-			proj.app.Stage = input.Stage
+			return data, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
 
-			if proj.app.Providers == nil {
-				proj.app.Providers = map[string]interface{}{}
-			}
+func (proj *Project) parseApp(data []byte, input *ProjectConfig) error {
+	var parsed App
+	err := json.Unmarshal(data, &parsed)
+	if err != nil {
+		return err
+	}
+	proj.app = &parsed
+	proj.app.Stage = input.Stage
 
-			for name, args := range proj.app.Providers {
-				if argsBool, ok := args.(bool); ok && argsBool {
-					proj.app.Providers[name] = make(map[string]interface{})
-				}
+	if proj.app.Providers == nil {
+		proj.app.Providers = map[string]interface{}{}
+	}
 
-				if argsString, ok := args.(string); ok {
-					proj.app.Providers[name] = map[string]interface{}{
-						"version": argsString,
-					}
-				}
-			}
+	for name, args := range proj.app.Providers {
+		if argsBool, ok := args.(bool); ok && argsBool {
+			proj.app.Providers[name] = make(map[string]interface{})
+		}
 
-			if proj.app.Name == "" {
-				return nil, fmt.Errorf("Project name is required")
+		if argsString, ok := args.(string); ok {
+			proj.app.Providers[name] = map[string]interface{}{
+				"version": argsString,
 			}
+		}
+	}
 
-			if InvalidAppRegex.MatchString(proj.app.Name) {
-				return nil, ErrInvalidAppName
-			}
+	if proj.app.Name == "" {
+		return fmt.Errorf("Project name is required")
+	}
 
-			// Check if app name has changed by comparing the folder name inside ".pulumi/stacks"
-			// and the app name in the config file.
-			stacksDir := filepath.Join(proj.PathWorkingDir(), ".pulumi", "stacks")
-			files, err := os.ReadDir(stacksDir)
-			if err != nil {
-				if !os.IsNotExist(err) {
-					return nil, err
-				}
-				files = []os.DirEntry{}
-			}
-			if len(files) > 0 {
-				appName := files[0].Name()
-				if appName != proj.app.Name {
-					return nil, ErrAppNameChanged
-				}
-			}
+	if InvalidAppRegex.MatchString(proj.app.Name) {
+		return ErrInvalidAppName
+	}
 
-			if proj.app.Home == "" {
-				return nil, util.NewReadableError(nil, `You must specify a "home" provider in the project configuration file.`)
-			}
+	// Check if app name has changed by comparing the folder name inside ".pulumi/stacks"
+	// and the app name in the config file.
+	stacksDir := filepath.Join(proj.PathWorkingDir(), ".pulumi", "stacks")
+	files, err := os.ReadDir(stacksDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		files = []os.DirEntry{}
+	}
+	if len(files) > 0 {
+		appName := files[0].Name()
+		if appName != proj.app.Name {
+			return ErrAppNameChanged
+		}
+	}
 
-			if _, ok := proj.app.Providers[proj.app.Home]; !ok && proj.app.Home != "local" {
-				proj.app.Providers[proj.app.Home] = map[string]interface{}{}
-			}
+	if proj.app.Home == "" {
+		return util.NewReadableError(nil, `You must specify a "home" provider in the project configuration file.`)
+	}
 
-			if proj.app.RemovalPolicy != "" {
-				return nil, util.NewReadableError(nil, `The "removalPolicy" has been renamed to "removal"`)
-			}
+	if _, ok := proj.app.Providers[proj.app.Home]; !ok && proj.app.Home != "local" {
+		proj.app.Providers[proj.app.Home] = map[string]interface{}{}
+	}
 
-			if proj.app.Removal == "" {
-				proj.app.Removal = "retain"
-			}
+	if proj.app.RemovalPolicy != "" {
+		return util.NewReadableError(nil, `The "removalPolicy" has been renamed to "removal"`)
+	}
 
-			if proj.app.Version != "" && input.Version != "dev" {
-				constraint, err := semver.NewConstraint(proj.app.Version)
-				if err != nil {
-					return nil, ErrVersionInvalid
-				}
-				version, err := semver.NewVersion(input.Version)
-				if err != nil {
-					return nil, ErrVersionInvalid
-				}
-				if !constraint.Check(version) {
-					return nil, &ErrVersionMismatch{Needed: input.Version, Received: proj.app.Version}
-				}
-			}
+	if proj.app.Removal == "" {
+		proj.app.Removal = "retain"
+	}
 
-			if proj.app.Removal != "remove" && proj.app.Removal != "retain" && proj.app.Removal != "retain-all" {
-				return nil, fmt.Errorf("Removal must be one of: remove, retain, retain-all")
-			}
-			continue
+	if proj.app.Version != "" && input.Version != "dev" {
+		constraint, err := semver.NewConstraint(proj.app.Version)
+		if err != nil {
+			return ErrVersionInvalid
+		}
+		version, err := semver.NewVersion(input.Version)
+		if err != nil {
+			return ErrVersionInvalid
+		}
+		if !constraint.Check(version) {
+			return &ErrVersionMismatch{Needed: input.Version, Received: proj.app.Version}
 		}
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
 	}
 
-	err = proj.loadProviderLock()
-	if err != nil {
-		return nil, err
+	if proj.app.Removal != "remove" && proj.app.Removal != "retain" && proj.app.Removal != "retain-all" {
+		return fmt.Errorf("Removal must be one of: remove, retain, retain-all")
 	}
-
-	return proj, nil
+	return nil
 }
 
 func (proj *Project) LoadHome() error {
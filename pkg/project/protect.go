@@ -0,0 +1,130 @@
+package project
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/pkg/bus"
+)
+
+// ProtectedChange is a delete or replace of a resource that is protected, or
+// is inside a protected component. Name is the protected component.
+type ProtectedChange struct {
+	URN     string
+	Name    string
+	Op      string
+	Allowed bool
+}
+
+type ProtectedEvent struct {
+	Changes []ProtectedChange
+}
+
+type ErrProtectedResource struct {
+	Names []string
+}
+
+func (err *ErrProtectedResource) Error() string {
+	return "protected resources would be destroyed: " + strings.Join(err.Names, ", ")
+}
+
+// destructive ops are the ones that delete the physical resource at some point
+var destructive = []apitype.OpType{
+	apitype.OpDelete,
+	apitype.OpReplace,
+	apitype.OpCreateReplacement,
+	apitype.OpDeleteReplaced,
+}
+
+// protectedURNs maps every resource in the state that is protected to the
+// name it was protected under. Everything inside a protected component is
+// protected as well. Names that aren't in the state yet are ignored since
+// there is nothing to destroy.
+func protectedURNs(resources []apitype.ResourceV3, names []string) map[string]string {
+	result := map[string]string{}
+	for _, res := range resources {
+		if slices.Contains(names, res.URN.Name()) {
+			result[string(res.URN)] = res.URN.Name()
+			continue
+		}
+		// parents always come before their children
+		if name, ok := result[string(res.Parent)]; ok {
+			result[string(res.URN)] = name
+		}
+	}
+	return result
+}
+
+// checkProtected finds the steps that would destroy a protected resource. A
+// change is allowed if the component or the resource itself is in allowed.
+// Only the first destructive step of each resource is reported.
+func checkProtected(protected map[string]string, allowed []string, steps []apitype.StepEventMetadata) []ProtectedChange {
+	result := []ProtectedChange{}
+	seen := map[string]bool{}
+	for _, step := range steps {
+		name, ok := protected[string(step.URN)]
+		if !ok || seen[string(step.URN)] || !slices.Contains(destructive, step.Op) {
+			continue
+		}
+		// retained resources are only dropped from the state
+		if step.Old != nil && step.Old.RetainOnDelete {
+			continue
+		}
+		seen[string(step.URN)] = true
+		result = append(result, ProtectedChange{
+			URN:     string(step.URN),
+			Name:    name,
+			Op:      string(step.Op),
+			Allowed: slices.Contains(allowed, name) || slices.Contains(allowed, resource.URN(step.URN).Name()),
+		})
+	}
+	return result
+}
+
+// removeSteps are the deletes a remove would run, since there is no preview
+// for it. With targets only they and their dependents are removed.
+func removeSteps(resources []apitype.ResourceV3, targets []string) []apitype.StepEventMetadata {
+	removed := withDependents(resources, targets)
+	result := []apitype.StepEventMetadata{}
+	for _, res := range resources {
+		if len(targets) > 0 && !slices.Contains(removed, string(res.URN)) {
+			continue
+		}
+		result = append(result, apitype.StepEventMetadata{
+			Op:   apitype.OpDelete,
+			URN:  string(res.URN),
+			Type: string(res.Type),
+			Old: &apitype.StepEventStateMetadata{
+				URN:            string(res.URN),
+				Type:           string(res.Type),
+				RetainOnDelete: res.RetainOnDelete,
+			},
+		})
+	}
+	return result
+}
+
+// blockedNames are the protected components with changes that aren't allowed.
+func blockedNames(changes []ProtectedChange) []string {
+	result := []string{}
+	for _, change := range changes {
+		if !change.Allowed && !slices.Contains(result, change.Name) {
+			result = append(result, change.Name)
+		}
+	}
+	return result
+}
+
+func (p *Project) checkProtected(protected map[string]string, allowed []string, steps []apitype.StepEventMetadata) error {
+	changes := checkProtected(protected, allowed, steps)
+	if len(changes) == 0 {
+		return nil
+	}
+	bus.Publish(&ProtectedEvent{Changes: changes})
+	if blocked := blockedNames(changes); len(blocked) > 0 {
+		return &ErrProtectedResource{Names: blocked}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/sst/sst/v3/pkg/id"
+	"golang.org/x/sync/errgroup"
+)
+
+type AuditResult string
+
+const (
+	AuditResultSucceeded AuditResult = "succeeded"
+	AuditResultFailed    AuditResult = "failed"
+)
+
+// AuditRecord is a command that changed a stage. User is the user on the
+// machine the command was run from and Identity is who they were in the home,
+// like the ARN of the AWS role.
+type AuditRecord struct {
+	ID       string        `json:"id"`
+	App      string        `json:"app"`
+	Stage    string        `json:"stage"`
+	Command  string        `json:"command"`
+	Args     []string      `json:"args"`
+	User     string        `json:"user"`
+	Identity string        `json:"identity,omitempty"`
+	Version  string        `json:"version"`
+	Commit   string        `json:"commit,omitempty"`
+	Dirty    bool          `json:"dirty"`
+	Result   AuditResult   `json:"result"`
+	Error    string        `json:"error,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+}
+
+// PutAudit stores the record on its own so records are only ever added, never
+// rewritten. The IDs sort newest first.
+func PutAudit(backend Home, record *AuditRecord) error {
+	if record.ID == "" {
+		record.ID = id.Descending()
+	}
+	slog.Info("putting audit", "app", record.App, "stage", record.Stage, "command", record.Command)
+	return putData(backend, "audit", record.App, record.Stage+"/"+record.ID, false, record)
+}
+
+// ListAudit returns the records of the stage, or every stage if it's empty,
+// newest first. Records that were put before since are left out without being
+// fetched. The IDs of a stage sort newest first, so listing it stops at the
+// first one that's too old.
+func ListAudit(backend Home, app, stage string, since time.Time) ([]AuditRecord, error) {
+	// a record is put once its command is done, so one put before since
+	// also started before it
+	older := func(name string) bool {
+		if since.IsZero() {
+			return false
+		}
+		_, recordID, _ := strings.Cut(name, "/")
+		created, err := id.DescendingTime(recordID)
+		return err == nil && created.Before(since)
+	}
+	prefix := ""
+	var stop func(name string) bool
+	if stage != "" {
+		prefix = stage + "/"
+		stop = older
+	}
+	names, err := backend.listData("audit", app, prefix, stop)
+	if err != nil {
+		return nil, err
+	}
+	result := []AuditRecord{}
+	var lock sync.Mutex
+	var wg errgroup.Group
+	wg.SetLimit(10)
+	for _, name := range names {
+		stage, id, ok := strings.Cut(name, "/")
+		if !ok || older(name) {
+			continue
+		}
+		wg.Go(func() error {
+			var record AuditRecord
+			err := getData(backend, "audit", app, stage+"/"+id, false, &record)
+			if err != nil {
+				return err
+			}
+			lock.Lock()
+			result = append(result, record)
+			lock.Unlock()
+			return nil
+		})
+	}
+	if err := wg.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Started.After(result[j].Started)
+	})
+	return result, nil
+}
+
+// Identity returns who the credentials of the home belong to, if it can tell.
+func Identity(backend Home) string {
+	switch home := backend.(type) {
+	case *AwsHome:
+		identity, err := sts.NewFromConfig(home.provider.config).GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+		if err != nil {
+			slog.Error("could not get the aws identity", "err", err)
+			return ""
+		}
+		return aws.ToString(identity.Arn)
+	case *CloudflareHome:
+		return "cloudflare:" + home.provider.defaultAccountId
+	}
+	return ""
+}
+
+// AuditFilter narrows down audit records. Empty fields match everything.
+type AuditFilter struct {
+	User    string
+	Command string
+	Since   time.Time
+	Until   time.Time
+}
+
+// Match checks the record against the filter. The command matches on its
+// leading words so `state` matches `state edit` and `state remove`.
+func (f AuditFilter) Match(record AuditRecord) bool {
+	if f.User != "" && f.User != record.User && f.User != record.Identity {
+		return false
+	}
+	if f.Command != "" && record.Command != f.Command && !strings.HasPrefix(record.Command, f.Command+" ") {
+		return false
+	}
+	if !f.Since.IsZero() && record.Started.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Started.After(f.Until) {
+		return false
+	}
+	return true
+}
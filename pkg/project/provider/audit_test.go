@@ -0,0 +1,43 @@
+package provider_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sst/sst/v3/pkg/project/provider"
+)
+
+func TestAuditFilter(t *testing.T) {
+	started := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	record := provider.AuditRecord{
+		Command:  "state edit",
+		User:     "jay",
+		Identity: "arn:aws:sts::123456789012:assumed-role/deploy/jay",
+		Started:  started,
+	}
+	tests := []struct {
+		name   string
+		filter provider.AuditFilter
+		match  bool
+	}{
+		{"empty", provider.AuditFilter{}, true},
+		{"user", provider.AuditFilter{User: "jay"}, true},
+		{"identity", provider.AuditFilter{User: record.Identity}, true},
+		{"other user", provider.AuditFilter{User: "frank"}, false},
+		{"command", provider.AuditFilter{Command: "state edit"}, true},
+		{"parent command", provider.AuditFilter{Command: "state"}, true},
+		{"partial command", provider.AuditFilter{Command: "sta"}, false},
+		{"other command", provider.AuditFilter{Command: "deploy"}, false},
+		{"since", provider.AuditFilter{Since: started.Add(-time.Hour)}, true},
+		{"since after", provider.AuditFilter{Since: started.Add(time.Hour)}, false},
+		{"until", provider.AuditFilter{Until: started.Add(time.Hour)}, true},
+		{"until before", provider.AuditFilter{Until: started.Add(-time.Hour)}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.filter.Match(record); got != test.match {
+				t.Errorf("Match() = %v, want %v", got, test.match)
+			}
+		})
+	}
+}
@@ -76,6 +76,10 @@ func (a *AwsProvider) Init(app string, stage string, args map[string]interface{}
 	if value := os.Getenv("AWS_PROFILE"); value != "" {
 		a.profile = value
 	}
+	expect, err := popExpectation(args, "account", "region")
+	if err != nil {
+		return err
+	}
 
 	cfg, err := config.LoadDefaultConfig(
 		ctx,
@@ -109,6 +113,10 @@ func (a *AwsProvider) Init(app string, stage string, args map[string]interface{}
 	}
 	slog.Info("aws credentials found", "region", cfg.Region, "profile", a.profile)
 	a.config = cfg
+	err = a.checkExpectation(ctx, expect, stage)
+	if err != nil {
+		return err
+	}
 	defaultTags, ok := args["defaultTags"].(map[string]interface{})
 	if !ok {
 		defaultTags = map[string]interface{}{}
@@ -131,6 +139,29 @@ func (a *AwsProvider) Init(app string, stage string, args map[string]interface{}
 	return nil
 }
 
+// checkExpectation makes sure the credentials are for the account and region
+// the stage expects before anything is bootstrapped or deployed.
+func (a *AwsProvider) checkExpectation(ctx context.Context, expect expectation, stage string) error {
+	hint := "Check the credentials you are using"
+	if a.profile != "" {
+		hint = "Check the " + a.profile + " profile"
+	}
+	hint += ", or run with the right AWS_PROFILE."
+	err := expect.check("AWS", stage, "region", a.config.Region, hint)
+	if err != nil {
+		return err
+	}
+	if len(expect["account"]) == 0 {
+		return nil
+	}
+	identity, err := sts.NewFromConfig(a.config).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("could not check the account: %w", err)
+	}
+	slog.Info("aws identity", "account", aws.ToString(identity.Account), "arn", aws.ToString(identity.Arn))
+	return expect.check("AWS", stage, "account", aws.ToString(identity.Account), hint)
+}
+
 func (a *AwsProvider) Config() aws.Config {
 	return a.config
 }
@@ -146,24 +177,9 @@ func (p *AwsProvider) Bootstrap(region string) (*AwsBootstrapData, error) {
 	cfg := p.config.Copy()
 	cfg.Region = region
 	ssmClient := ssm.NewFromConfig(cfg)
-	bootstrapData := &AwsBootstrapData{}
-	slog.Info("fetching bootstrap")
-	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String(SSM_NAME_BOOTSTRAP),
-		WithDecryption: aws.Bool(false),
-	})
-	if result != nil && result.Parameter.Value != nil {
-		slog.Info("found existing bootstrap", "data", *result.Parameter.Value)
-		err = json.Unmarshal([]byte(*result.Parameter.Value), bootstrapData)
-		if err != nil {
-			return nil, err
-		}
-	}
+	bootstrapData, err := fetchBootstrap(ctx, ssmClient)
 	if err != nil {
-		var pnf *ssmTypes.ParameterNotFound
-		if !errors.As(err, &pnf) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	if len(steps) > bootstrapData.Version {
@@ -196,6 +212,43 @@ func (p *AwsProvider) Bootstrap(region string) (*AwsBootstrapData, error) {
 	return bootstrapData, nil
 }
 
+// LookupBootstrap returns the bootstrap data of the region without creating
+// or upgrading anything. It's empty if the region was never bootstrapped.
+func (p *AwsProvider) LookupBootstrap(region string) (*AwsBootstrapData, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	match, ok := p.bootstrapCache[region]
+	if ok {
+		return match, nil
+	}
+	cfg := p.config.Copy()
+	cfg.Region = region
+	return fetchBootstrap(context.TODO(), ssm.NewFromConfig(cfg))
+}
+
+func fetchBootstrap(ctx context.Context, ssmClient *ssm.Client) (*AwsBootstrapData, error) {
+	bootstrapData := &AwsBootstrapData{}
+	slog.Info("fetching bootstrap")
+	result, err := ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(SSM_NAME_BOOTSTRAP),
+		WithDecryption: aws.Bool(false),
+	})
+	if result != nil && result.Parameter.Value != nil {
+		slog.Info("found existing bootstrap", "data", *result.Parameter.Value)
+		err = json.Unmarshal([]byte(*result.Parameter.Value), bootstrapData)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		var pnf *ssmTypes.ParameterNotFound
+		if !errors.As(err, &pnf) {
+			return nil, err
+		}
+	}
+	return bootstrapData, nil
+}
+
 func (app *AwsProvider) ResolveAppSync(ctx context.Context) (string, string, error) {
 	client := appsyncSdk.NewFromConfig(app.config)
 	var nextToken *string
@@ -729,6 +782,38 @@ func (a *AwsHome) listStages(app string) ([]string, error) {
 	return stages, nil
 }
 
+// listData lists what is stored for the key and app, under the prefix. The
+// names are the ones that were passed in as the stage. They are listed in
+// order and the listing ends at the first one that stop returns true for.
+func (a *AwsHome) listData(key, app, prefix string, stop func(name string) bool) ([]string, error) {
+	bootstrap, err := a.provider.Bootstrap(a.provider.config.Region)
+	if err != nil {
+		return nil, err
+	}
+	s3Client := s3.NewFromConfig(a.provider.config)
+
+	base := path.Join(key, app) + "/"
+	result := []string{}
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bootstrap.State),
+		Prefix: aws.String(base + prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			name := strings.TrimSuffix(strings.TrimPrefix(*obj.Key, base), ".json")
+			if stop != nil && stop(name) {
+				return result, nil
+			}
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
 func (c *AwsHome) info() (util.KeyValuePairs[string], error) {
 	caller := sts.NewFromConfig(c.provider.config)
 	identity, err := caller.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
@@ -742,6 +827,19 @@ func (c *AwsHome) info() (util.KeyValuePairs[string], error) {
 		{Key: "Account", Value: *identity.Account},
 	}
 
+	bootstrap, err := c.provider.LookupBootstrap(c.provider.config.Region)
+	if err != nil {
+		return nil, err
+	}
+	if bootstrap.State == "" {
+		lines = append(lines, util.KeyValuePair[string]{Key: "State", Value: "Not bootstrapped"})
+	} else {
+		lines = append(lines,
+			util.KeyValuePair[string]{Key: "State", Value: bootstrap.State},
+			util.KeyValuePair[string]{Key: "Assets", Value: bootstrap.Asset},
+		)
+	}
+
 	if len(c.provider.profile) != 0 {
 		lines = append(lines, util.KeyValuePair[string]{
 			Key: "Profile", Value: c.provider.profile,
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -33,6 +34,10 @@ func (c *CloudflareProvider) Env() (map[string]string, error) {
 }
 
 func (c *CloudflareProvider) Init(app, stage string, args map[string]interface{}) error {
+	expect, err := popExpectation(args, "account")
+	if err != nil {
+		return err
+	}
 	apiToken := os.Getenv("CLOUDFLARE_API_TOKEN")
 	apiKey := os.Getenv("CLOUDFLARE_API_KEY")
 	email := os.Getenv("CLOUDFLARE_EMAIL")
@@ -70,6 +75,10 @@ func (c *CloudflareProvider) Init(app, stage string, args map[string]interface{}
 	c.defaultAccountId = accountID
 	c.identifier = cloudflare.AccountIdentifier(accountID)
 	slog.Info("cloudflare account selected", "account", accountID)
+	err = expect.check("Cloudflare", stage, "account", accountID, "Check your API token, or set CLOUDFLARE_DEFAULT_ACCOUNT_ID to the right account.")
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -225,9 +234,56 @@ func (c *CloudflareHome) listStages(app string) ([]string, error) {
 	return stages, nil
 }
 
+func (c *CloudflareHome) listData(kind, app, prefix string, stop func(name string) bool) ([]string, error) {
+	type r2Response struct {
+		Result []struct {
+			Key string `json:"key"`
+		} `json:"result"`
+		ResultInfo struct {
+			Cursor      string `json:"cursor"`
+			IsTruncated bool   `json:"is_truncated"`
+		} `json:"result_info"`
+	}
+
+	base := filepath.Join(kind, app) + "/"
+	result := []string{}
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", base+prefix)
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		data, err := makeRequestContext(c.provider.api, context.Background(), http.MethodGet, "/accounts/"+c.provider.identifier.Identifier+"/r2/buckets/"+c.bootstrap.State+"/objects?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var response r2Response
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range response.Result {
+			name := strings.TrimPrefix(obj.Key, base)
+			if stop != nil && stop(name) {
+				return result, nil
+			}
+			result = append(result, name)
+		}
+		if !response.ResultInfo.IsTruncated || response.ResultInfo.Cursor == "" {
+			return result, nil
+		}
+		cursor = response.ResultInfo.Cursor
+	}
+}
+
 func (c *CloudflareHome) info() (util.KeyValuePairs[string], error) {
-	return util.KeyValuePairs[string]{
+	lines := util.KeyValuePairs[string]{
 		{Key: "Provider", Value: "Cloudflare"},
 		{Key: "Account", Value: c.provider.identifier.Identifier},
-	}, nil
+	}
+	if c.bootstrap != nil {
+		lines = append(lines, util.KeyValuePair[string]{Key: "State", Value: c.bootstrap.State})
+	}
+	return lines, nil
 }
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnexpectedAccount is returned when the credentials of a provider are for
+// a different account or region than the stage expects.
+type ErrUnexpectedAccount struct {
+	Provider string
+	Stage    string
+	Field    string
+	Expected []string
+	Actual   string
+	Hint     string
+}
+
+func (err *ErrUnexpectedAccount) Error() string {
+	expected := strings.Join(err.Expected, " or ")
+	result := fmt.Sprintf("The %v stage expects the %v %v to be %v, but it is %v.", err.Stage, err.Provider, err.Field, expected, err.Actual)
+	if err.Hint != "" {
+		result += " " + err.Hint
+	}
+	return result
+}
+
+// expectation is what a stage expects a provider to be set up with. It's set
+// in the provider args as `expect`, and removed from them so it's not passed
+// on to the provider.
+type expectation map[string][]string
+
+func popExpectation(args map[string]interface{}, fields ...string) (expectation, error) {
+	value, ok := args["expect"]
+	delete(args, "expect")
+	if !ok || value == nil {
+		return expectation{}, nil
+	}
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expect should be an object like { account: \"123456789012\" }")
+	}
+	result := expectation{}
+	for key, value := range values {
+		if !slices.Contains(fields, key) {
+			return nil, fmt.Errorf("expect.%v is not supported, use %v", key, strings.Join(fields, " or "))
+		}
+		switch cast := value.(type) {
+		case string:
+			if cast != "" {
+				result[key] = []string{cast}
+			}
+		case []interface{}:
+			for _, item := range cast {
+				str, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expect.%v should be a string or a list of strings", key)
+				}
+				result[key] = append(result[key], str)
+			}
+		default:
+			return nil, fmt.Errorf("expect.%v should be a string or a list of strings", key)
+		}
+	}
+	return result, nil
+}
+
+func (e expectation) check(provider, stage, field, actual, hint string) error {
+	expected, ok := e[field]
+	if !ok || len(expected) == 0 {
+		return nil
+	}
+	for _, item := range expected {
+		if item == actual {
+			return nil
+		}
+	}
+	return &ErrUnexpectedAccount{
+		Provider: provider,
+		Stage:    stage,
+		Field:    field,
+		Expected: expected,
+		Actual:   actual,
+		Hint:     hint,
+	}
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPopExpectation(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected expectation
+		err      bool
+	}{
+		{
+			name:     "missing",
+			args:     map[string]interface{}{"region": "us-east-1"},
+			expected: expectation{},
+		},
+		{
+			name:     "null",
+			args:     map[string]interface{}{"expect": nil},
+			expected: expectation{},
+		},
+		{
+			name: "string",
+			args: map[string]interface{}{
+				"region": "us-east-1",
+				"expect": map[string]interface{}{"account": "123456789012", "region": "us-east-1"},
+			},
+			expected: expectation{"account": {"123456789012"}, "region": {"us-east-1"}},
+		},
+		{
+			name: "list",
+			args: map[string]interface{}{
+				"expect": map[string]interface{}{"region": []interface{}{"us-east-1", "us-west-2"}},
+			},
+			expected: expectation{"region": {"us-east-1", "us-west-2"}},
+		},
+		{
+			name: "empty string",
+			args: map[string]interface{}{
+				"expect": map[string]interface{}{"account": ""},
+			},
+			expected: expectation{},
+		},
+		{
+			name: "unknown key",
+			args: map[string]interface{}{
+				"expect": map[string]interface{}{"profile": "production"},
+			},
+			err: true,
+		},
+		{
+			name: "not an object",
+			args: map[string]interface{}{"expect": "123456789012"},
+			err:  true,
+		},
+		{
+			name: "not a string",
+			args: map[string]interface{}{
+				"expect": map[string]interface{}{"account": 123456789012},
+			},
+			err: true,
+		},
+		{
+			name: "list with a number",
+			args: map[string]interface{}{
+				"expect": map[string]interface{}{"account": []interface{}{"123456789012", 42}},
+			},
+			err: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := popExpectation(test.args, "account", "region")
+			if _, ok := test.args["expect"]; ok {
+				t.Fatal("expected expect to be removed from the args")
+			}
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestPopExpectationKeepsProviderArgs(t *testing.T) {
+	args := map[string]interface{}{
+		"region":  "us-east-1",
+		"profile": "production",
+		"expect":  map[string]interface{}{"account": "123456789012"},
+	}
+	if _, err := popExpectation(args, "account", "region"); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"region": "us-east-1", "profile": "production"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+}
+
+func TestExpectationCheck(t *testing.T) {
+	expect := expectation{
+		"account": {"123456789012", "210987654321"},
+		"region":  {},
+	}
+	tests := []struct {
+		name   string
+		field  string
+		actual string
+		err    bool
+	}{
+		{"matches", "account", "123456789012", false},
+		{"matches another", "account", "210987654321", false},
+		{"mismatch", "account", "000000000000", true},
+		{"empty list", "region", "eu-west-1", false},
+		{"not expected", "zone", "anything", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := expect.check("AWS", "production", test.field, test.actual, "Check your profile.")
+			if !test.err {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var unexpected *ErrUnexpectedAccount
+			if !errors.As(err, &unexpected) {
+				t.Fatalf("expected ErrUnexpectedAccount, got %v", err)
+			}
+			if unexpected.Stage != "production" || unexpected.Field != test.field || unexpected.Actual != test.actual {
+				t.Fatalf("unexpected error fields %+v", unexpected)
+			}
+			expected := "The production stage expects the AWS account to be 123456789012 or 210987654321, but it is 000000000000. Check your profile."
+			if err.Error() != expected {
+				t.Fatalf("expected %q, got %q", expected, err.Error())
+			}
+		})
+	}
+}
@@ -4,19 +4,27 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/sst/sst/v3/internal/util"
 	"github.com/sst/sst/v3/pkg/global"
 )
 
 type LocalHome struct {
+	dir   string
+	mtx   sync.Mutex
+	locks map[string]*os.File
 }
 
 func NewLocalHome() *LocalHome {
-	return &LocalHome{}
+	return &LocalHome{
+		dir:   filepath.Join(global.ConfigDir(), "state"),
+		locks: map[string]*os.File{},
+	}
 }
 
 func (l *LocalHome) Bootstrap() error {
@@ -29,35 +37,46 @@ func (l *LocalHome) cleanup(key, app, stage string) error {
 
 func (l *LocalHome) getData(key, app, stage string) (io.Reader, error) {
 	p := l.pathForData(key, app, stage)
-	result, err := os.Open(p)
+	result, err := os.ReadFile(p)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return result, nil
+	return bytes.NewReader(result), nil
 }
 
 func (l *LocalHome) putData(key, app, stage string, data io.Reader) error {
 	if key == "summary" {
 		return nil
 	}
-	p := l.pathForData(key, app, stage)
-	err := os.MkdirAll(filepath.Dir(p), 0755)
+	return writeFile(l.pathForData(key, app, stage), data)
+}
+
+// writeFile writes to a temp file next to the path and renames it over the
+// path, so the file is never seen half written.
+func writeFile(path string, data io.Reader) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
 		return err
 	}
-	file, err := os.Create(p)
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 	_, err = io.Copy(file, data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
+		os.Remove(file.Name())
 		return err
 	}
-	return nil
+	return os.Rename(file.Name(), path)
 }
 
 func (l *LocalHome) removeData(key, app, stage string) error {
@@ -86,11 +105,11 @@ func (c *LocalHome) getPassphrase(app, stage string) (string, error) {
 }
 
 func (l *LocalHome) pathForData(key, app, stage string) string {
-	return filepath.Join(global.ConfigDir(), "state", key, app, fmt.Sprintf("%v.json", stage))
+	return filepath.Join(l.dir, key, app, fmt.Sprintf("%v.json", stage))
 }
 
 func (a *LocalHome) listStages(app string) ([]string, error) {
-	path := filepath.Join(global.ConfigDir(), "state", "app", app)
+	path := filepath.Join(a.dir, "app", app)
 
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -111,6 +130,39 @@ func (a *LocalHome) listStages(app string) ([]string, error) {
 	return stages, nil
 }
 
+func (l *LocalHome) listData(key, app, prefix string, stop func(name string) bool) ([]string, error) {
+	base := filepath.Join(l.dir, key, app)
+	result := []string{}
+	err := filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), ".json")
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		if stop != nil && stop(name) {
+			return filepath.SkipAll
+		}
+		result = append(result, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *LocalHome) info() (util.KeyValuePairs[string], error) {
 	return util.KeyValuePairs[string]{
 		{Key: "Provider", Value: "Local"},
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrLockHeld = fmt.Errorf("Another process on this machine is updating this stage")
+
+// lock takes the lock of the stage with a flock on a lock file next to it,
+// which is held until the stage is unlocked or the process exits. So only one
+// process on the machine can hold it, even if they start at the same time.
+//
+// If the lock was left behind by a process that's no longer running, it's
+// taken over. Locks without a PID were written by an older version and are
+// left to `sst unlock`.
+func (l *LocalHome) lock(app, stage string, data lockData) error {
+	p := l.pathForData("lock", app, stage)
+	err := os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(strings.TrimSuffix(p, ".json")+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	held, err := lockFile(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if !held {
+		file.Close()
+		return ErrLockHeld
+	}
+	release := func() {
+		unlockFile(file)
+		file.Close()
+	}
+
+	var existing lockData
+	err = getData(l, "lock", app, stage, false, &existing)
+	if err != nil {
+		release()
+		return err
+	}
+	if !existing.Created.IsZero() {
+		if existing.PID == 0 || processAlive(existing.PID) {
+			release()
+			return ErrLockExists
+		}
+		slog.Info("taking over stale lock", "app", app, "stage", stage, "pid", existing.PID)
+	}
+
+	data.PID = os.Getpid()
+	err = putData(l, "lock", app, stage, false, data)
+	if err != nil {
+		release()
+		return err
+	}
+	l.mtx.Lock()
+	l.locks[app+"/"+stage] = file
+	l.mtx.Unlock()
+	return nil
+}
+
+// unlock removes the lock and releases the lock file. The lock file itself is
+// kept, removing it would let two processes lock different files.
+func (l *LocalHome) unlock(app, stage string) error {
+	err := l.removeData("lock", app, stage)
+	l.mtx.Lock()
+	file, ok := l.locks[app+"/"+stage]
+	delete(l.locks, app+"/"+stage)
+	l.mtx.Unlock()
+	if ok {
+		unlockFile(file)
+		file.Close()
+	}
+	return err
+}
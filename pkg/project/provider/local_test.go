@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestLocalHome(t *testing.T) *LocalHome {
+	return &LocalHome{
+		dir:   t.TempDir(),
+		locks: map[string]*os.File{},
+	}
+}
+
+func TestLocalPutData(t *testing.T) {
+	home := newTestLocalHome(t)
+	for _, value := range []string{"first", "second"} {
+		if err := home.putData("app", "myapp", "dev", bytes.NewReader([]byte(value))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(home.dir, "app", "myapp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "dev.json" {
+		t.Fatalf("expected only dev.json, got %v", entries)
+	}
+	data, err := os.ReadFile(home.pathForData("app", "myapp", "dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Fatalf("expected second, got %q", data)
+	}
+}
+
+func TestLocalLock(t *testing.T) {
+	home := newTestLocalHome(t)
+	if _, err := Lock(home, "dev", "deploy", "myapp", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	other := &LocalHome{dir: home.dir, locks: map[string]*os.File{}}
+	if _, err := Lock(other, "dev", "deploy", "myapp", "dev"); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("expected ErrLockHeld, got %v", err)
+	}
+	if err := Unlock(home, "dev", "myapp", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lock(other, "dev", "deploy", "myapp", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Unlock(other, "dev", "myapp", "dev"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalLockStale(t *testing.T) {
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		pid  int
+		err  error
+	}{
+		{"exited", exited.Process.Pid, nil},
+		{"running", os.Getpid(), ErrLockExists},
+		{"no pid", 0, ErrLockExists},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := newTestLocalHome(t)
+			data, err := json.Marshal(lockData{Created: time.Now(), UpdateID: "previous", PID: test.pid})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := home.putData("lock", "myapp", "dev", bytes.NewReader(data)); err != nil {
+				t.Fatal(err)
+			}
+			_, err = Lock(home, "dev", "deploy", "myapp", "dev")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			if err == nil {
+				Unlock(home, "dev", "myapp", "dev")
+			}
+		})
+	}
+}
+
+func TestLocalListAuditSince(t *testing.T) {
+	home := newTestLocalHome(t)
+	put := func(stage string) {
+		err := PutAudit(home, &AuditRecord{App: "myapp", Stage: stage, Command: "deploy", Started: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	put("dev")
+	put("production")
+	since := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	put("dev")
+	put("dev")
+
+	tests := []struct {
+		name     string
+		stage    string
+		since    time.Time
+		expected int
+	}{
+		{"stage", "dev", time.Time{}, 3},
+		{"stage since", "dev", since, 2},
+		{"all stages", "", time.Time{}, 4},
+		{"all stages since", "", since, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := ListAudit(home, "myapp", test.stage, test.since)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != test.expected {
+				t.Fatalf("expected %d records, got %d", test.expected, len(records))
+			}
+		})
+	}
+}
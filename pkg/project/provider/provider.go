@@ -14,6 +14,7 @@ import (
 
 	"github.com/sst/sst/v3/internal/util"
 	"github.com/sst/sst/v3/pkg/flag"
+	"github.com/sst/sst/v3/pkg/freeze"
 	"github.com/sst/sst/v3/pkg/id"
 	"golang.org/x/exp/slog"
 )
@@ -26,6 +27,7 @@ type Home interface {
 	setPassphrase(app, stage string, passphrase string) error
 	getPassphrase(app, stage string) (string, error)
 	listStages(app string) ([]string, error)
+	listData(key, app, prefix string, stop func(name string) bool) ([]string, error)
 	cleanup(key, app, stage string) error
 	info() (util.KeyValuePairs[string], error)
 }
@@ -131,18 +133,22 @@ type Summary struct {
 }
 
 type SummaryError struct {
-	URN     string `json:"urn"`
-	Message string `json:"message"`
+	URN     string   `json:"urn"`
+	Message string   `json:"message"`
+	Codes   []string `json:"codes,omitempty"`
 }
 
 type Update struct {
-	ID            string         `json:"id"`
-	RunID         string         `json:"runID,omitempty"`
-	Version       string         `json:"version"`
-	Command       string         `json:"command"`
-	Errors        []SummaryError `json:"errors"`
-	TimeStarted   string         `json:"timeStarted"`
-	TimeCompleted string         `json:"timeCompleted,omitempty"`
+	ID             string         `json:"id"`
+	RunID          string         `json:"runID,omitempty"`
+	Version        string         `json:"version"`
+	Command        string         `json:"command"`
+	Errors         []SummaryError `json:"errors"`
+	TimeStarted    string         `json:"timeStarted"`
+	TimeCompleted  string         `json:"timeCompleted,omitempty"`
+	InputHash      string         `json:"inputHash,omitempty"`
+	Expires        string         `json:"expires,omitempty"`
+	FreezeOverride string         `json:"freezeOverride,omitempty"`
 }
 
 func PutSummary(backend Home, app, stage, updateID string, summary Summary) error {
@@ -156,6 +162,100 @@ func PutUpdate(backend Home, app, stage string, update *Update) error {
 	return putData(backend, "update", app, stage+"/"+update.ID, false, update)
 }
 
+type inputHashData struct {
+	UpdateID string `json:"updateID"`
+	Hash     string `json:"hash"`
+}
+
+// GetInputHash returns the input hash recorded by the last successful deploy,
+// or an empty string if the stage has changed in any other way since.
+func GetInputHash(backend Home, app, stage string) (string, error) {
+	var data inputHashData
+	err := getData(backend, "hash", app, stage, false, &data)
+	if err != nil {
+		return "", err
+	}
+	return data.Hash, nil
+}
+
+func PutInputHash(backend Home, app, stage, updateID, hash string) error {
+	slog.Info("putting input hash", "app", app, "stage", stage)
+	return putData(backend, "hash", app, stage, false, inputHashData{
+		UpdateID: updateID,
+		Hash:     hash,
+	})
+}
+
+func RemoveInputHash(backend Home, app, stage string) error {
+	hash, err := GetInputHash(backend, app, stage)
+	if err != nil || hash == "" {
+		return err
+	}
+	slog.Info("removing input hash", "app", app, "stage", stage)
+	return removeData(backend, "hash", app, stage)
+}
+
+type expiryData struct {
+	UpdateID string    `json:"updateID"`
+	Expires  time.Time `json:"expires"`
+}
+
+// GetExpiry returns when the stage expires, or a zero time if it was never
+// deployed with a TTL.
+func GetExpiry(backend Home, app, stage string) (time.Time, error) {
+	var data expiryData
+	err := getData(backend, "expiry", app, stage, false, &data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return data.Expires, nil
+}
+
+func PutExpiry(backend Home, app, stage, updateID string, expires time.Time) error {
+	slog.Info("putting expiry", "app", app, "stage", stage, "expires", expires)
+	return putData(backend, "expiry", app, stage, false, expiryData{
+		UpdateID: updateID,
+		Expires:  expires,
+	})
+}
+
+func RemoveExpiry(backend Home, app, stage string) error {
+	expires, err := GetExpiry(backend, app, stage)
+	if err != nil || expires.IsZero() {
+		return err
+	}
+	slog.Info("removing expiry", "app", app, "stage", stage)
+	return removeData(backend, "expiry", app, stage)
+}
+
+// GetFreeze returns the freeze windows of the stage that are stored in the
+// home, on top of the ones in the config.
+func GetFreeze(backend Home, app, stage string) ([]freeze.Window, error) {
+	windows := []freeze.Window{}
+	err := getData(backend, "freeze", app, stage, false, &windows)
+	if err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+func PutFreeze(backend Home, app, stage string, windows []freeze.Window) error {
+	if len(windows) == 0 {
+		return RemoveFreeze(backend, app, stage)
+	}
+	slog.Info("putting freeze", "app", app, "stage", stage, "windows", len(windows))
+	return putData(backend, "freeze", app, stage, false, windows)
+}
+
+func RemoveFreeze(backend Home, app, stage string) error {
+	windows, err := GetFreeze(backend, app, stage)
+	if err != nil || len(windows) == 0 {
+		return err
+	}
+	slog.Info("removing freeze", "app", app, "stage", stage)
+	return removeData(backend, "freeze", app, stage)
+}
+
 func Cleanup(backend Home, app, stage string) error {
 	if err := backend.cleanup("eventlog", app, stage); err != nil {
 		return err
@@ -241,27 +341,44 @@ type lockData struct {
 	RunID    string    `json:"runID"`
 	Command  string    `json:"command"`
 	Ignore   bool      `json:"ignore"`
+	PID      int       `json:"pid,omitempty"`
+}
+
+// locker is implemented by homes that can take the lock of a stage in one
+// step, instead of checking for it and then writing it.
+type locker interface {
+	lock(app, stage string, data lockData) error
+	unlock(app, stage string) error
 }
 
 func Lock(backend Home, version, command, app, stage string) (*Update, error) {
 	updateID := id.Descending()
 	slog.Info("locking", "app", app, "stage", stage)
-	var lockData lockData
-	err := getData(backend, "lock", app, stage, false, &lockData)
-	if err != nil {
-		return nil, err
-	}
-	if !lockData.Created.IsZero() {
-		return nil, ErrLockExists
-	}
-	lockData.RunID = os.Getenv("SST_RUN_ID")
-	lockData.Created = time.Now()
-	lockData.UpdateID = updateID
-	lockData.Command = command
-	lockData.Ignore = true
-	err = putData(backend, "lock", app, stage, false, lockData)
-	if err != nil {
-		return nil, err
+	data := lockData{
+		RunID:    os.Getenv("SST_RUN_ID"),
+		Created:  time.Now(),
+		UpdateID: updateID,
+		Command:  command,
+		Ignore:   true,
+	}
+	if locker, ok := backend.(locker); ok {
+		err := locker.lock(app, stage, data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var existing lockData
+		err := getData(backend, "lock", app, stage, false, &existing)
+		if err != nil {
+			return nil, err
+		}
+		if !existing.Created.IsZero() {
+			return nil, ErrLockExists
+		}
+		err = putData(backend, "lock", app, stage, false, data)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	update := &Update{
@@ -271,7 +388,7 @@ func Lock(backend Home, version, command, app, stage string) (*Update, error) {
 		Errors:      nil,
 		TimeStarted: time.Now().UTC().Format(time.RFC3339),
 	}
-	err = PutUpdate(backend, app, stage, update)
+	err := PutUpdate(backend, app, stage, update)
 	if err != nil {
 		return nil, err
 	}
@@ -279,8 +396,21 @@ func Lock(backend Home, version, command, app, stage string) (*Update, error) {
 	return update, nil
 }
 
+// IsLocked reports whether an update is currently running on the stage.
+func IsLocked(backend Home, app, stage string) (bool, error) {
+	var lockData lockData
+	err := getData(backend, "lock", app, stage, false, &lockData)
+	if err != nil {
+		return false, err
+	}
+	return !lockData.Created.IsZero(), nil
+}
+
 func Unlock(backend Home, version, app, stage string) error {
 	slog.Info("unlocking", "app", app, "stage", stage)
+	if locker, ok := backend.(locker); ok {
+		return locker.unlock(app, stage)
+	}
 	return removeData(backend, "lock", app, stage)
 }
 
@@ -308,6 +438,11 @@ func ForceUnlock(backend Home, version, app, stage string) error {
 			return err
 		}
 	}
+	// the update may have changed the state before it was stopped
+	err = RemoveInputHash(backend, app, stage)
+	if err != nil {
+		return err
+	}
 	return removeData(backend, "lock", app, stage)
 }
 
@@ -0,0 +1,151 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/internal/util"
+	"github.com/sst/sst/v3/pkg/project/provider"
+	"github.com/sst/sst/v3/pkg/state"
+)
+
+// Reference points at the outputs of another app or stage in the same home.
+// The stage defaults to the current one.
+type Reference struct {
+	App   string `json:"app"`
+	Stage string `json:"stage"`
+}
+
+// resolveReferences reads the outputs of every reference in the config from
+// the state in the home. Each app and stage is only read once per run.
+func (p *Project) resolveReferences(ctx context.Context, workdir *PulumiWorkdir) (map[string]map[string]interface{}, error) {
+	log := slog.Default().With("service", "project.reference")
+	result := map[string]map[string]interface{}{}
+	cache := map[string]map[string]interface{}{}
+	for name, ref := range p.app.References {
+		app := ref.App
+		if app == "" {
+			app = p.app.Name
+		}
+		stage := ref.Stage
+		if stage == "" {
+			stage = p.app.Stage
+		}
+		if app == p.app.Name && stage == p.app.Stage {
+			return nil, util.NewReadableError(nil, fmt.Sprintf("Reference %v points to the stage that is being deployed", name))
+		}
+		key := app + "/" + stage
+		outputs, ok := cache[key]
+		if !ok {
+			log.Info("resolving reference", "name", name, "app", app, "stage", stage)
+			var err error
+			outputs, err = p.readOutputs(ctx, workdir, app, stage)
+			if err != nil {
+				return nil, err
+			}
+			cache[key] = outputs
+		}
+		result[name] = outputs
+	}
+	return result, nil
+}
+
+func (p *Project) readOutputs(ctx context.Context, workdir *PulumiWorkdir, app, stage string) (map[string]interface{}, error) {
+	locked, err := provider.IsLocked(p.home, app, stage)
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		return nil, util.NewReadableError(nil, fmt.Sprintf("Cannot read %v/%v while it is being updated. Try again once it's done.", app, stage))
+	}
+	path := filepath.Join(workdir.path, "references", app, stage+".json")
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	err = provider.PullState(p.home, app, stage, path)
+	if err != nil {
+		if errors.Is(err, provider.ErrStateNotFound) {
+			return nil, util.NewReadableError(err, fmt.Sprintf("Cannot reference %v/%v because it has never been deployed. Run `sst deploy --stage %v` in that app first.", app, stage, stage))
+		}
+		return nil, err
+	}
+	checkpoint, err := readCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	// reading the passphrase only after the state exists, otherwise a new one
+	// would be created for the stage
+	passphrase, err := provider.Passphrase(p.home, app, stage)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := state.Decrypt(ctx, passphrase, checkpoint)
+	if err != nil {
+		return nil, util.NewReadableError(err, fmt.Sprintf("Could not decrypt the state of %v/%v", app, stage))
+	}
+	return ReferenceOutputs(decrypted), nil
+}
+
+// ReferenceSecretKey wraps the values that are secret in the referenced stage,
+// so the Reference component can keep them secret in this one.
+const ReferenceSecretKey = "__sstSecret"
+
+// ReferenceOutputs returns the outputs of the stack in a decrypted checkpoint.
+// Secret values are wrapped in an object with ReferenceSecretKey instead of
+// being turned into plain values.
+func ReferenceOutputs(checkpoint *apitype.CheckpointV3) map[string]interface{} {
+	outputs := map[string]interface{}{}
+	if checkpoint.Latest == nil || len(checkpoint.Latest.Resources) == 0 {
+		return outputs
+	}
+	for key, value := range checkpoint.Latest.Resources[0].Outputs {
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		outputs[key] = wrapSecrets(value)
+	}
+	return outputs
+}
+
+func wrapSecrets(input interface{}) interface{} {
+	switch cast := input.(type) {
+	case apitype.SecretV1:
+		var parsed any
+		json.Unmarshal([]byte(cast.Plaintext), &parsed)
+		return map[string]interface{}{ReferenceSecretKey: parsed}
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, value := range cast {
+			result[key] = wrapSecrets(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(cast))
+		for i, value := range cast {
+			result[i] = wrapSecrets(value)
+		}
+		return result
+	default:
+		return cast
+	}
+}
+
+func referenceEnv(references map[string]map[string]interface{}) ([]string, error) {
+	result := []string{}
+	for name, outputs := range references {
+		data, err := json.Marshal(outputs)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, "SST_REFERENCE_"+name+"="+string(data))
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package project_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestReferenceOutputs(t *testing.T) {
+	checkpoint := &apitype.CheckpointV3{
+		Latest: &apitype.DeploymentV3{
+			Resources: []apitype.ResourceV3{
+				{
+					URN: "urn:pulumi:production::backend::pulumi:pulumi:Stack::backend-production",
+					Outputs: map[string]interface{}{
+						"api":      "https://api.example.com",
+						"password": apitype.SecretV1{Plaintext: `"hunter2"`},
+						"database": map[string]interface{}{
+							"host": "db.example.com",
+							"port": apitype.SecretV1{Plaintext: `5432`},
+						},
+						"_protect": true,
+					},
+				},
+			},
+		},
+	}
+	outputs := project.ReferenceOutputs(checkpoint)
+	if outputs["api"] != "https://api.example.com" {
+		t.Errorf("expected api to stay plain, got %v", outputs["api"])
+	}
+	if _, ok := outputs["_protect"]; ok {
+		t.Error("expected internal outputs to be skipped")
+	}
+	password, ok := outputs["password"].(map[string]interface{})
+	if !ok || password[project.ReferenceSecretKey] != "hunter2" {
+		t.Errorf("expected password to stay secret, got %v", outputs["password"])
+	}
+	database := outputs["database"].(map[string]interface{})
+	if database["host"] != "db.example.com" {
+		t.Errorf("expected host to stay plain, got %v", database["host"])
+	}
+	port, ok := database["port"].(map[string]interface{})
+	if !ok || port[project.ReferenceSecretKey] != float64(5432) {
+		t.Errorf("expected port to stay secret, got %v", database["port"])
+	}
+
+	data, err := json.Marshal(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"password":{"`+project.ReferenceSecretKey+`":"hunter2"}`) {
+		t.Errorf("expected the secret to be marked in the JSON, got %s", data)
+	}
+}
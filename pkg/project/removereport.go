@@ -0,0 +1,100 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// RemoveResource is a resource a remove deletes or leaves behind. ID is the
+// physical ID of the resource in the provider.
+type RemoveResource struct {
+	URN  string `json:"urn"`
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+// RemovePreviewEvent is published by `sst remove --dry-run` with what the
+// remove would do. Retained resources are kept because of the removal policy
+// and are only dropped from the state. StageProtected is set when the stage
+// has `protect` on, in which case the remove would be refused.
+type RemovePreviewEvent struct {
+	Deleted        []RemoveResource
+	Retained       []RemoveResource
+	Protected      []ProtectedChange
+	StageProtected bool
+}
+
+// RetainedEvent is published after a remove with the resources that were left
+// behind because of the removal policy. These need to be cleaned up by hand.
+type RetainedEvent struct {
+	Resources []RemoveResource `json:"resources"`
+}
+
+func (evt *RetainedEvent) Write(path string) error {
+	data, err := json.MarshalIndent(evt, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// removePreview sorts the steps of a destroy preview into the resources that
+// would be deleted and the ones that would be retained. Components are left
+// out since there is nothing to delete for them.
+func removePreview(resources []apitype.ResourceV3, steps []apitype.StepEventMetadata, protected map[string]string, allowed []string) *RemovePreviewEvent {
+	ids := map[string]string{}
+	custom := map[string]bool{}
+	for _, res := range resources {
+		ids[string(res.URN)] = string(res.ID)
+		custom[string(res.URN)] = res.Custom
+	}
+	result := &RemovePreviewEvent{
+		Deleted:   []RemoveResource{},
+		Retained:  []RemoveResource{},
+		Protected: checkProtected(protected, allowed, steps),
+	}
+	for _, step := range steps {
+		if step.Op != apitype.OpDelete || !custom[step.URN] {
+			continue
+		}
+		item := RemoveResource{
+			URN:  step.URN,
+			Type: step.Type,
+			ID:   ids[step.URN],
+		}
+		if step.Old != nil && step.Old.RetainOnDelete {
+			result.Retained = append(result.Retained, item)
+			continue
+		}
+		result.Deleted = append(result.Deleted, item)
+	}
+	return result
+}
+
+// retainedResources are the resources that were retained on delete and are
+// no longer in the state after a remove.
+func retainedResources(before []apitype.ResourceV3, after []apitype.ResourceV3) []RemoveResource {
+	result := []RemoveResource{}
+	for _, res := range before {
+		if !res.Custom || !res.RetainOnDelete {
+			continue
+		}
+		if slices.ContainsFunc(after, func(item apitype.ResourceV3) bool { return item.URN == res.URN }) {
+			continue
+		}
+		result = append(result, RemoveResource{
+			URN:  string(res.URN),
+			Type: string(res.Type),
+			ID:   string(res.ID),
+		})
+	}
+	return result
+}
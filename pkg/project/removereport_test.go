@@ -0,0 +1,128 @@
+package project
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+const removePrefix = "urn:pulumi:dev::app::"
+
+var removeResources = []apitype.ResourceV3{
+	{URN: removePrefix + "sst:aws:Bucket::MyBucket"},
+	{URN: removePrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket", Parent: removePrefix + "sst:aws:Bucket::MyBucket", Custom: true, ID: "my-bucket"},
+	{URN: removePrefix + "sst:aws:Postgres::Database"},
+	{URN: removePrefix + "sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster", Parent: removePrefix + "sst:aws:Postgres::Database", Custom: true, ID: "database"},
+	{URN: removePrefix + "aws:dynamodb/table:Table::Table", Custom: true, ID: "table", RetainOnDelete: true},
+}
+
+func deleteStep(urn string, retain bool) apitype.StepEventMetadata {
+	return apitype.StepEventMetadata{
+		Op:  apitype.OpDelete,
+		URN: urn,
+		Old: &apitype.StepEventStateMetadata{URN: urn, RetainOnDelete: retain},
+	}
+}
+
+func removeURNs(items []RemoveResource) []string {
+	result := []string{}
+	for _, item := range items {
+		result = append(result, item.URN)
+	}
+	return result
+}
+
+func TestRemovePreview(t *testing.T) {
+	steps := []apitype.StepEventMetadata{
+		deleteStep(removePrefix+"sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket", false),
+		deleteStep(removePrefix+"sst:aws:Bucket::MyBucket", false),
+		deleteStep(removePrefix+"sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster", false),
+		deleteStep(removePrefix+"sst:aws:Postgres::Database", false),
+		deleteStep(removePrefix+"aws:dynamodb/table:Table::Table", true),
+	}
+	protected := protectedURNs(removeResources, []string{"Database"})
+	tests := []struct {
+		name      string
+		allowed   []string
+		deleted   []string
+		retained  []string
+		protected []ProtectedChange
+	}{
+		{
+			name: "blocked",
+			deleted: []string{
+				removePrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket",
+				removePrefix + "sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster",
+			},
+			retained: []string{removePrefix + "aws:dynamodb/table:Table::Table"},
+			protected: []ProtectedChange{
+				{URN: removePrefix + "sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster", Name: "Database", Op: string(apitype.OpDelete)},
+				{URN: removePrefix + "sst:aws:Postgres::Database", Name: "Database", Op: string(apitype.OpDelete)},
+			},
+		},
+		{
+			name:    "allowed",
+			allowed: []string{"Database"},
+			deleted: []string{
+				removePrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket",
+				removePrefix + "sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster",
+			},
+			retained: []string{removePrefix + "aws:dynamodb/table:Table::Table"},
+			protected: []ProtectedChange{
+				{URN: removePrefix + "sst:aws:Postgres$aws:rds/cluster:Cluster::DatabaseCluster", Name: "Database", Op: string(apitype.OpDelete), Allowed: true},
+				{URN: removePrefix + "sst:aws:Postgres::Database", Name: "Database", Op: string(apitype.OpDelete), Allowed: true},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := removePreview(removeResources, steps, protected, test.allowed)
+			if deleted := removeURNs(got.Deleted); !slices.Equal(deleted, test.deleted) {
+				t.Errorf("expected deleted %v, got %v", test.deleted, deleted)
+			}
+			if retained := removeURNs(got.Retained); !slices.Equal(retained, test.retained) {
+				t.Errorf("expected retained %v, got %v", test.retained, retained)
+			}
+			if len(got.Protected) != len(test.protected) {
+				t.Fatalf("expected protected %v, got %v", test.protected, got.Protected)
+			}
+			for i := range got.Protected {
+				if got.Protected[i] != test.protected[i] {
+					t.Errorf("expected protected %v, got %v", test.protected[i], got.Protected[i])
+				}
+			}
+			if got.Deleted[0].ID != "my-bucket" {
+				t.Errorf("expected the id of the bucket, got %q", got.Deleted[0].ID)
+			}
+		})
+	}
+}
+
+func TestRetainedResources(t *testing.T) {
+	table := removeResources[4]
+	tests := []struct {
+		name     string
+		after    []apitype.ResourceV3
+		expected []string
+	}{
+		{
+			name:     "removed",
+			after:    []apitype.ResourceV3{},
+			expected: []string{removePrefix + "aws:dynamodb/table:Table::Table"},
+		},
+		{
+			name:     "still in the state after a partial remove",
+			after:    []apitype.ResourceV3{table},
+			expected: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := removeURNs(retainedResources(removeResources, test.after))
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/sst/sst/v3/pkg/id"
 	"github.com/sst/sst/v3/pkg/js"
 	"github.com/sst/sst/v3/pkg/process"
+	"github.com/sst/sst/v3/pkg/project/policy"
 	"github.com/sst/sst/v3/pkg/project/provider"
 	"github.com/sst/sst/v3/pkg/telemetry"
 	"github.com/sst/sst/v3/pkg/types"
@@ -40,17 +41,36 @@ func (p *Project) Run(ctx context.Context, input *StackInput) error {
 	return p.RunNext(ctx, input)
 }
 
-func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
+func (p *Project) RunNext(ctx context.Context, input *StackInput) (err error) {
 	log := slog.Default().With("service", "project.run")
 	log.Info("running stack command", "cmd", input.Command)
 
-	if p.app.Protect && input.Command == "remove" {
+	if p.app.Protect && input.Command == "remove" && !input.DryRun {
 		return ErrProtectedStage
 	}
 
+	policies, err := policy.Load(p.PathRoot())
+	if err != nil {
+		return util.NewReadableError(err, err.Error())
+	}
+
+	commonErrors, err := LoadCommonErrors(p.PathRoot())
+	if err != nil {
+		return util.NewReadableError(err, err.Error())
+	}
+
+	var plan *Plan
+	if input.Plan != "" {
+		plan, err = LoadPlan(input.Plan)
+		if err != nil {
+			return util.NewReadableError(err, "Could not read plan "+input.Plan)
+		}
+	}
+
 	bus.Publish(&StackCommandEvent{
 		App:     p.app.Name,
 		Stage:   p.app.Stage,
+		Branch:  p.branch,
 		Config:  p.PathConfig(),
 		Command: input.Command,
 		Version: p.Version(),
@@ -59,8 +79,13 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	update := &provider.Update{
 		ID: id.Descending(),
 	}
-	var err error
-	if input.Command != "diff" {
+	// a dry run only reads the state so it doesn't need the lock
+	readonly := input.Command == "diff" || input.DryRun
+	if !readonly {
+		override, err := p.checkFreeze(input.OverrideFreeze)
+		if err != nil {
+			return err
+		}
 		update, err = p.Lock(input.Command)
 		if err != nil {
 			if err == provider.ErrLockExists {
@@ -68,6 +93,7 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 			}
 			return err
 		}
+		update.FreezeOverride = override
 		log = log.With("updateID", update.ID)
 		defer p.Unlock()
 	}
@@ -149,6 +175,7 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 		"state": map[string]interface{}{
 			"version": completed.Versions,
 		},
+		"imports": input.Imports,
 	}
 	cliBytes, err := json.Marshal(cli)
 	if err != nil {
@@ -197,12 +224,6 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 		defer js.Cleanup(buildResult)
 	}
 
-	// disable for now until we hash env too
-	if input.SkipHash != "" && buildResult.OutputFiles[0].Hash == input.SkipHash && false {
-		bus.Publish(&SkipEvent{})
-		return nil
-	}
-
 	var meta = js.Metafile{}
 	err = json.Unmarshal([]byte(buildResult.Metafile), &meta)
 	if err != nil {
@@ -219,10 +240,22 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	}
 	bus.Publish(&BuildSuccessEvent{
 		Files: files,
-		Hash:  buildResult.OutputFiles[0].Hash,
 	})
 	log.Info("tracked files")
 
+	configHash := ""
+	stateHash := ""
+	if input.SavePlan != "" || plan != nil {
+		configHash, err = hashFiles(p.PathRoot(), files)
+		if err != nil {
+			return err
+		}
+		stateHash, err = hashFiles(workdir.path, []string{workdir.state()})
+		if err != nil {
+			return err
+		}
+	}
+
 	secrets := map[string]string{}
 	fallback := map[string]string{}
 
@@ -248,6 +281,78 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 		return err
 	}
 
+	// only deploys and diffs run the program
+	references := map[string]map[string]interface{}{}
+	if input.Command == "deploy" || input.Command == "diff" {
+		references, err = p.resolveReferences(ctx, workdir)
+		if err != nil {
+			return err
+		}
+	}
+	referenceBytes, err := json.Marshal(references)
+	if err != nil {
+		return err
+	}
+
+	// the paths differ between checkouts of the same app, so they are left
+	// out of the hash
+	hashedCli := map[string]interface{}{}
+	for key, value := range cli {
+		if key != "paths" {
+			hashedCli[key] = value
+		}
+	}
+	hashedCliBytes, err := json.Marshal(hashedCli)
+	if err != nil {
+		return err
+	}
+	inputHash, err := p.inputHash(files, map[string]string{
+		"app":        string(appBytes),
+		"cli":        string(hashedCliBytes),
+		"references": string(referenceBytes),
+	}, fallback, secrets)
+	if err != nil {
+		return err
+	}
+	update.InputHash = inputHash
+	if input.Command == "deploy" && !input.Force && len(input.Replace) == 0 && len(input.Imports) == 0 && plan == nil {
+		skipHash := input.SkipHash
+		if skipHash == "" && !input.Dev {
+			skipHash, err = provider.GetInputHash(p.home, p.app.Name, p.app.Stage)
+			if err != nil {
+				return err
+			}
+		}
+		if skipHash == inputHash {
+			log.Info("nothing changed since the last deploy", "hash", inputHash)
+			err = p.putExpiry(input.TTL, update)
+			if err != nil {
+				return err
+			}
+			update.TimeCompleted = time.Now().Format(time.RFC3339)
+			err = provider.PutUpdate(p.home, p.app.Name, p.app.Stage, update)
+			if err != nil {
+				return err
+			}
+			bus.Publish(&SkipEvent{})
+			return nil
+		}
+	}
+	// cleared up front so a deploy that never finishes can't be skipped
+	// next time, it's put back once the deploy succeeds
+	if input.Command != "diff" && !input.DryRun {
+		err = provider.RemoveInputHash(p.home, p.app.Name, p.app.Stage)
+		if err != nil {
+			return err
+		}
+	}
+	if input.Dev {
+		started := time.Now()
+		defer func() {
+			p.auditDev(started, err)
+		}()
+	}
+
 	env := os.Environ()
 	for key, value := range p.Env() {
 		env = append(env, fmt.Sprintf("%v=%v", key, value))
@@ -258,6 +363,11 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	for key, value := range secrets {
 		env = append(env, fmt.Sprintf("SST_SECRET_%v=%v", key, value))
 	}
+	referenceEnv, err := referenceEnv(references)
+	if err != nil {
+		return err
+	}
+	env = append(env, referenceEnv...)
 	env = append(env,
 		"PULUMI_CONFIG_PASSPHRASE="+passphrase,
 		"PULUMI_SKIP_UPDATE_CHECK=true",
@@ -270,6 +380,10 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	if input.ServerPort != 0 {
 		env = append(env, "SST_SERVER=http://127.0.0.1:"+fmt.Sprint(input.ServerPort))
 	}
+	if input.SavePlan != "" || plan != nil {
+		// update plans are still experimental in pulumi
+		env = append(env, "PULUMI_EXPERIMENTAL=true")
+	}
 	pulumiPath := global.PulumiPath()
 	if flag.SST_PULUMI_PATH != "" {
 		pulumiPath = flag.SST_PULUMI_PATH
@@ -285,7 +399,6 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	args := []string{
 		"--stack", fmt.Sprintf("organization/%v/%v", p.app.Name, p.app.Stage),
 		"--non-interactive",
-		"--event-log", eventlogPath,
 	}
 
 	if input.Command == "deploy" || input.Command == "diff" {
@@ -311,6 +424,152 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 		}
 	}
 
+	targets, err := resolveURNs(completed.Resources, "Target", input.Target)
+	if err != nil {
+		return err
+	}
+	excludes, err := resolveURNs(completed.Resources, "Exclude", input.Exclude)
+	if err != nil {
+		return err
+	}
+	replaces, err := resolveURNs(completed.Resources, "Replace", input.Replace)
+	if err != nil {
+		return err
+	}
+	replaces = withCustomChildren(completed.Resources, replaces)
+	if len(excludes) > 0 {
+		// the bundled pulumi has no --exclude so target everything else in
+		// the state instead
+		excludes = withDependents(completed.Resources, excludes)
+		included := withDependents(completed.Resources, targets)
+		if len(targets) == 0 {
+			included = []string{}
+			for _, res := range completed.Resources {
+				included = append(included, string(res.URN))
+			}
+		}
+		targets = slices.DeleteFunc(included, func(urn string) bool {
+			return slices.Contains(excludes, urn)
+		})
+		// resources that are new to the config aren't in the state yet, so
+		// they are found with a preview and targeted as well
+		if input.Command == "deploy" || input.Command == "diff" {
+			steps, err := p.preview(ctx, workdir, pulumiPath, args, env, pulumiStdout, pulumiStderr)
+			if err != nil {
+				return err
+			}
+			within := targets
+			if len(input.Target) == 0 {
+				within = nil
+			}
+			targets = append(targets, pendingCreates(steps, within, excludes)...)
+		}
+		if len(targets) == 0 {
+			return util.NewReadableError(nil, "Every resource is excluded, there is nothing to run")
+		}
+	}
+	for _, urn := range targets {
+		args = append(args, "--target", urn)
+	}
+	if len(input.Target) > 0 && len(excludes) == 0 {
+		args = append(args, "--target-dependents")
+	}
+	for _, urn := range replaces {
+		args = append(args, "--replace", urn)
+	}
+	if input.Parallel > 0 {
+		args = append(args, "--parallel", fmt.Sprint(input.Parallel))
+	}
+	if len(input.Target) > 0 || len(excludes) > 0 || len(replaces) > 0 {
+		event := &TargetsEvent{
+			Target:  []string{},
+			Exclude: excludes,
+			Replace: replaces,
+		}
+		if len(input.Target) > 0 {
+			event.Target = targets
+		}
+		bus.Publish(event)
+	}
+
+	protected := protectedURNs(completed.Resources, p.app.Protected)
+	if input.Command == "remove" && input.DryRun {
+		steps, err := p.previewDestroy(ctx, workdir, pulumiPath, args, env, pulumiStdout, pulumiStderr)
+		if err != nil {
+			return err
+		}
+		preview := removePreview(completed.Resources, steps, protected, input.AllowDestroy)
+		preview.StageProtected = p.app.Protect
+		bus.Publish(preview)
+		return nil
+	}
+	if input.Command == "remove" && len(protected) > 0 {
+		err = p.checkProtected(protected, input.AllowDestroy, removeSteps(completed.Resources, targets))
+		if err != nil {
+			return err
+		}
+	}
+
+	// the steps planned before a --continue update, the ones that never run
+	// are reported as skipped
+	var planned []apitype.StepEventMetadata
+	if input.Command == "remove" && input.Continue {
+		planned, err = p.previewDestroy(ctx, workdir, pulumiPath, args, env, pulumiStdout, pulumiStderr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if input.Command == "deploy" && (len(policies.Rules) > 0 || plan != nil || len(protected) > 0 || input.Continue) {
+		// the up is held to the plan of the preview when components are
+		// protected, otherwise it could delete one the preview didn't
+		previewArgs := args
+		previewPlanPath := filepath.Join(workdir.path, "preview.plan.json")
+		if plan == nil && len(protected) > 0 {
+			env = append(env, "PULUMI_EXPERIMENTAL=true")
+			previewArgs = append(slices.Clone(args), "--save-plan", previewPlanPath)
+		}
+		steps, err := p.preview(ctx, workdir, pulumiPath, previewArgs, env, pulumiStdout, pulumiStderr)
+		if err != nil {
+			return err
+		}
+		if plan == nil && len(protected) > 0 {
+			args = append(args, "--plan", previewPlanPath)
+		}
+		planned = steps
+		violations := policies.Evaluate(p.app.Stage, steps)
+		if len(violations) > 0 {
+			bus.Publish(&PolicyEvent{Violations: violations})
+		}
+		if policy.Mandatory(violations) {
+			return ErrPolicyViolation
+		}
+		err = p.checkProtected(protected, input.AllowDestroy, steps)
+		if err != nil {
+			return err
+		}
+		if plan != nil {
+			err = plan.Compare(p.app.Name, p.app.Stage, configHash, stateHash, steps)
+			if err != nil {
+				return err
+			}
+			if len(plan.Pulumi) > 0 {
+				pulumiPlanPath := filepath.Join(workdir.path, "plan.json")
+				err = os.WriteFile(pulumiPlanPath, plan.Pulumi, 0644)
+				if err != nil {
+					return err
+				}
+				args = append(args, "--plan", pulumiPlanPath)
+			}
+		}
+	}
+
+	pulumiPlanPath := filepath.Join(workdir.path, "plan.json")
+	if input.Command == "diff" && input.SavePlan != "" {
+		args = append(args, "--save-plan", pulumiPlanPath)
+	}
+
+	args = append(args, "--event-log", eventlogPath)
 	switch input.Command {
 	case "diff":
 		args = append([]string{"preview"}, args...)
@@ -321,20 +580,8 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	case "remove":
 		args = append([]string{"destroy", "--yes", "-f"}, args...)
 	}
-
-	if input.Target != nil {
-		for _, item := range input.Target {
-			index := slices.IndexFunc(completed.Resources, func(res apitype.ResourceV3) bool {
-				return res.URN.Name() == item
-			})
-			if index == -1 {
-				return util.NewReadableError(nil, fmt.Sprintf("Target not found: %v", item))
-			}
-			args = append(args, "--target", string(completed.Resources[index].URN))
-		}
-		if len(input.Target) > 0 {
-			args = append(args, "--target-dependents")
-		}
+	if input.Continue && (input.Command == "deploy" || input.Command == "remove") {
+		args = append(args, "--continue-on-error")
 	}
 
 	cmd := process.Command(pulumiPath, args...)
@@ -346,6 +593,10 @@ func (p *Project) RunNext(ctx context.Context, input *StackInput) error {
 	log.Info("starting pulumi", "args", cmd.Args)
 
 	errors := []Error{}
+	steps := []apitype.StepEventMetadata{}
+	succeeded := []string{}
+	failed := []string{}
+	timer := newTimer()
 	finished := false
 	importDiffs := map[string][]ImportDiff{}
 
@@ -447,47 +698,68 @@ loop:
 		}
 
 		if event.DiagnosticEvent != nil && event.DiagnosticEvent.Severity == "error" {
-			if strings.HasPrefix(event.DiagnosticEvent.Message, "update failed") || strings.Contains(event.DiagnosticEvent.Message, "failed to register new resource") {
-				continue
-			}
-
 			// check if the error is a common error
+			matched := MatchCommonErrors(commonErrors, event.DiagnosticEvent.Message, event.DiagnosticEvent.URN)
 			help := []string{}
-			for _, commonError := range CommonErrors {
-				if strings.Contains(event.DiagnosticEvent.Message, commonError.Message) {
-					help = append(help, commonError.Short...)
-				}
+			codes := []string{}
+			for _, commonError := range matched {
+				help = append(help, commonError.Help()...)
+				codes = append(codes, commonError.Code)
 			}
 
-			exists := false
+			index := -1
 			if event.DiagnosticEvent.URN != "" {
-				for _, item := range errors {
-					if item.URN == event.DiagnosticEvent.URN {
-						exists = true
-						break
+				index = slices.IndexFunc(errors, func(item Error) bool {
+					return item.URN == event.DiagnosticEvent.URN
+				})
+			}
+
+			// the same resource can report more than one error so attach any
+			// rules the others match to the first one
+			if index != -1 {
+				for _, commonError := range matched {
+					if slices.Contains(errors[index].Codes, commonError.Code) {
+						continue
 					}
+					errors[index].Codes = append(errors[index].Codes, commonError.Code)
+					errors[index].Help = append(errors[index].Help, commonError.Help()...)
 				}
+				continue
 			}
 
-			if exists {
+			if strings.HasPrefix(event.DiagnosticEvent.Message, "update failed") || strings.Contains(event.DiagnosticEvent.Message, "failed to register new resource") {
 				continue
 			}
 
-			if !exists {
-				errors = append(errors, Error{
-					Message: strings.TrimSpace(event.DiagnosticEvent.Message),
-					URN:     event.DiagnosticEvent.URN,
-					Help:    help,
-				})
-				log.Info("telemetry tracking error")
-				telemetry.Track("cli.resource.error", map[string]interface{}{
-					"error": event.DiagnosticEvent.Message,
-					"urn":   event.DiagnosticEvent.URN,
-				})
+			errors = append(errors, Error{
+				Message: strings.TrimSpace(event.DiagnosticEvent.Message),
+				URN:     event.DiagnosticEvent.URN,
+				Help:    help,
+				Codes:   codes,
+			})
+			log.Info("telemetry tracking error")
+			telemetry.Track("cli.resource.error", map[string]interface{}{
+				"error": event.DiagnosticEvent.Message,
+				"urn":   event.DiagnosticEvent.URN,
+				"codes": codes,
+			})
+		}
+
+		if event.ResourcePreEvent != nil {
+			steps = append(steps, event.ResourcePreEvent.Metadata)
+			timer.start(event.ResourcePreEvent.Metadata, time.Now())
+		}
+
+		if event.ResOutputsEvent != nil {
+			timer.finish(event.ResOutputsEvent.Metadata, time.Now())
+			if event.ResOutputsEvent.Metadata.Op != apitype.OpSame {
+				succeeded = append(succeeded, event.ResOutputsEvent.Metadata.URN)
 			}
 		}
 
 		if event.ResOpFailedEvent != nil {
+			timer.finish(event.ResOpFailedEvent.Metadata, time.Now())
+			failed = append(failed, event.ResOpFailedEvent.Metadata.URN)
 			if event.ResOpFailedEvent.Metadata.Op == apitype.OpImport {
 				for _, name := range event.ResOpFailedEvent.Metadata.Diffs {
 					old := event.ResOpFailedEvent.Metadata.Old.Inputs[name]
@@ -528,9 +800,36 @@ loop:
 	complete.Finished = finished
 	complete.Errors = errors
 	complete.ImportDiffs = importDiffs
+	complete.InputHash = inputHash
+	if input.Continue {
+		for _, err := range errors {
+			failed = append(failed, err.URN)
+		}
+		complete.Summary = summarize(planned, succeeded, failed)
+	}
 	types.Generate(p.PathConfig(), complete.Links)
+	// deferred first so the report comes after the summary
+	if len(input.Imports) > 0 {
+		defer bus.Publish(NewImportReport(input.Imports, completed.Resources, complete.Resources, errors, importDiffs))
+	}
 	defer bus.Publish(complete)
 
+	if input.Command != "diff" {
+		// the state is empty after a remove so use the one from before it
+		resources := complete.Resources
+		if input.Command == "remove" {
+			resources = completed.Resources
+		}
+		timings := timer.event(resources, input.Command == "remove")
+		err = timings.Write(filepath.Join(p.PathWorkingDir(), "timings.json"))
+		if err != nil {
+			return err
+		}
+		if len(timings.Resources) > 0 {
+			bus.Publish(timings)
+		}
+	}
+
 	if input.Command != "diff" {
 		log.Info("canceling partial")
 		partialCancel()
@@ -543,17 +842,43 @@ loop:
 		}
 	}
 
+	err = WriteImportFixes(filepath.Join(p.PathWorkingDir(), "import-fixes.json"), importDiffs)
+	if err != nil {
+		return err
+	}
+
 	outputsFilePath := filepath.Join(p.PathWorkingDir(), "outputs.json")
 	outputsFile, _ := os.Create(outputsFilePath)
 	defer outputsFile.Close()
 	json.NewEncoder(outputsFile).Encode(complete.Outputs)
 
+	if input.Command != "diff" {
+		// only a full deploy that succeeded can be skipped next time, anything
+		// else leaves the stage in a state the inputs don't describe
+		if input.Command == "deploy" && !input.Dev && len(input.Target) == 0 && len(input.Exclude) == 0 && len(errors) == 0 && finished && cmd.ProcessState.ExitCode() == 0 {
+			err = provider.PutInputHash(p.home, p.app.Name, p.app.Stage, update.ID, inputHash)
+		} else {
+			err = provider.RemoveInputHash(p.home, p.app.Name, p.app.Stage)
+		}
+		if err != nil {
+			return err
+		}
+		// the expiry only changes once the deploy went through
+		if input.Command == "deploy" && len(errors) == 0 && finished && cmd.ProcessState.ExitCode() == 0 {
+			err = p.putExpiry(input.TTL, update)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if input.Command != "diff " {
 		update.TimeCompleted = time.Now().Format(time.RFC3339)
 		for _, err := range errors {
 			update.Errors = append(update.Errors, provider.SummaryError{
 				URN:     err.URN,
 				Message: err.Message,
+				Codes:   err.Codes,
 			})
 		}
 		err = provider.PutUpdate(p.home, p.app.Name, p.app.Stage, update)
@@ -562,13 +887,79 @@ loop:
 		}
 	}
 
+	if input.Command == "remove" {
+		retained := &RetainedEvent{Resources: retainedResources(completed.Resources, complete.Resources)}
+		err = retained.Write(filepath.Join(p.PathWorkingDir(), "retained.json"))
+		if err != nil {
+			return err
+		}
+		if len(retained.Resources) > 0 {
+			bus.Publish(retained)
+		}
+	}
+
 	if input.Command == "remove" && len(complete.Resources) == 0 {
 		provider.Cleanup(p.home, p.app.Name, p.app.Stage)
+		err = provider.RemoveExpiry(p.home, p.app.Name, p.app.Stage)
+		if err != nil {
+			return err
+		}
 	}
 
 	log.Info("done running stack command", "resources", len(complete.Resources))
 	if cmd.ProcessState.ExitCode() > 0 {
 		return ErrStackRunFailed
 	}
+
+	if input.Command == "diff" {
+		// only shown, the deploy is what gets stopped
+		changes := checkProtected(protected, input.AllowDestroy, steps)
+		if len(changes) > 0 {
+			bus.Publish(&ProtectedEvent{Changes: changes})
+		}
+		violations := policies.Evaluate(p.app.Stage, steps)
+		if len(violations) > 0 {
+			bus.Publish(&PolicyEvent{Violations: violations})
+		}
+		if policy.Mandatory(violations) {
+			return ErrPolicyViolation
+		}
+	}
+
+	// a plan that breaks a mandatory policy is never saved
+	if input.Command == "diff" && input.SavePlan != "" {
+		pulumiPlan, err := os.ReadFile(pulumiPlanPath)
+		if err != nil {
+			return err
+		}
+		saved := &Plan{
+			Version:    p.Version(),
+			App:        p.app.Name,
+			Stage:      p.app.Stage,
+			ConfigHash: configHash,
+			StateHash:  stateHash,
+			Steps:      planSteps(steps),
+			Pulumi:     pulumiPlan,
+		}
+		err = saved.Write(input.SavePlan)
+		if err != nil {
+			return err
+		}
+		log.Info("saved plan", "path", input.SavePlan)
+	}
 	return nil
 }
+
+// putExpiry sets when the stage expires from the --ttl of a deploy, or clears
+// it when the ttl is 0. It's left alone when there's no ttl.
+func (p *Project) putExpiry(ttl *time.Duration, update *provider.Update) error {
+	if ttl == nil {
+		return nil
+	}
+	if *ttl == 0 {
+		return provider.RemoveExpiry(p.home, p.app.Name, p.app.Stage)
+	}
+	expires := time.Now().Add(*ttl).UTC()
+	update.Expires = expires.Format(time.RFC3339)
+	return provider.PutExpiry(p.home, p.app.Name, p.app.Stage, update.ID, expires)
+}
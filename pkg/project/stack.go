@@ -3,9 +3,11 @@ package project
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/sst/sst/v3/pkg/project/common"
+	"github.com/sst/sst/v3/pkg/project/policy"
 	"github.com/sst/sst/v3/pkg/project/provider"
 )
 
@@ -14,13 +16,24 @@ type BuildFailedEvent struct {
 }
 
 type StackInput struct {
-	Command    string
-	Target     []string
-	ServerPort int
-	Dev        bool
-	Verbose    bool
-	Continue   bool
-	SkipHash   string
+	Command        string
+	Target         []string
+	Exclude        []string
+	Replace        []string
+	Parallel       int
+	ServerPort     int
+	Dev            bool
+	Verbose        bool
+	Continue       bool
+	Force          bool
+	DryRun         bool
+	AllowDestroy   []string
+	TTL            *time.Duration
+	Imports        []ImportEntry
+	OverrideFreeze string
+	SkipHash       string
+	SavePlan       string
+	Plan           string
 }
 
 type ConcurrentUpdateEvent struct{}
@@ -29,12 +42,15 @@ type CancelledEvent struct{}
 
 type BuildSuccessEvent struct {
 	Files []string
-	Hash  string
 }
 
 type SkipEvent struct {
 }
 
+type PolicyEvent struct {
+	Violations []policy.Violation
+}
+
 type Dev struct {
 	Name        string            `json:"name"`
 	Command     string            `json:"command"`
@@ -69,6 +85,8 @@ type CompleteEvent struct {
 	Resources   []apitype.ResourceV3
 	ImportDiffs map[string][]ImportDiff
 	Tunnels     map[string]Tunnel
+	Summary     *UpdateSummary
+	InputHash   string
 }
 
 type Tunnel struct {
@@ -88,6 +106,7 @@ type ImportDiff struct {
 type StackCommandEvent struct {
 	App     string
 	Stage   string
+	Branch  string
 	Config  string
 	Command string
 	Version string
@@ -97,13 +116,22 @@ type Error struct {
 	Message string   `json:"message"`
 	URN     string   `json:"urn"`
 	Help    []string `json:"help"`
+	Codes   []string `json:"codes,omitempty"`
 }
 
+// CommonError is a rule that recognizes an error and explains how to fix it.
+// By default it matches errors that contain the Message. Match and Type can
+// be set to match the message or the type of the resource that failed with a
+// substring or a regex instead.
 type CommonError struct {
 	Code    string   `json:"code"`
 	Message string   `json:"message"`
 	Short   []string `json:"short"`
 	Long    []string `json:"long"`
+	Match   *Matcher `json:"match,omitempty"`
+	Type    *Matcher `json:"type,omitempty"`
+	Docs    string   `json:"docs,omitempty"`
+	Command string   `json:"command,omitempty"`
 }
 
 var CommonErrors = []CommonError{
@@ -112,8 +140,8 @@ var CommonErrors = []CommonError{
 		Message: "TooManyCacheBehaviors: Your request contains more CacheBehaviors than are allowed per distribution",
 		Short: []string{
 			"There are too many top-level files and directories inside your app's public asset directory. Move some of them inside subdirectories.",
-			"Learn more about this https://sst.dev/docs/common-errors#toomanycachebehaviors",
 		},
+		Docs: "https://sst.dev/docs/common-errors#toomanycachebehaviors",
 		Long: []string{
 			"This error usually happens to `SvelteKit`, `SolidStart`, `Nuxt`, and `Analog` components.",
 			"",
@@ -139,12 +167,35 @@ var CommonErrors = []CommonError{
 			"Learn more about these [CloudFront limits](https://docs.aws.amazon.com/AmazonCloudFront/latest/DeveloperGuide/cloudfront-limits.html#limits-web-distributions).",
 		},
 	},
+	{
+		Code:    "ExpiredToken",
+		Message: "ExpiredToken: The security token included in the request is expired",
+		Match: &Matcher{
+			Regex: `ExpiredToken|[Ss]ecurity token included in the request is expired`,
+		},
+		Short: []string{
+			"Your AWS credentials expired while deploying. Refresh them and run the command again.",
+		},
+		Docs: "https://sst.dev/docs/common-errors#expiredtoken",
+		Long: []string{
+			"This happens when the AWS credentials used by the CLI expire in the middle of a command. It's common with short lived credentials, like the ones from AWS SSO.",
+			"",
+			"If you are using SSO, log in again and rerun the command.",
+			"",
+			"```bash frame=\"none\"",
+			"aws sso login --profile <profile>",
+			"```",
+			"",
+			"Resources that were being updated when the credentials expired might be left in a pending state. The next deploy will pick them up.",
+		},
+	},
 }
 
 var ErrStackRunFailed = fmt.Errorf("stack run had errors")
 var ErrStageNotFound = fmt.Errorf("stage not found")
 var ErrPassphraseInvalid = fmt.Errorf("passphrase invalid")
 var ErrProtectedStage = fmt.Errorf("cannot remove protected stage")
+var ErrPolicyViolation = fmt.Errorf("mandatory policy violated")
 
 func (p *Project) Lock(command string) (*provider.Update, error) {
 	return provider.Lock(p.home, p.Version(), command, p.app.Name, p.app.Stage)
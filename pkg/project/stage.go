@@ -1,9 +1,15 @@
 package project
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/sst/sst/v3/pkg/process"
 )
 
 func resolveStageFile(cfgPath string) string {
@@ -22,9 +28,96 @@ func LoadPersonalStage(cfgPath string) string {
 }
 
 func SetPersonalStage(cfgPath string, stage string) error {
-	err := os.WriteFile(resolveStageFile(cfgPath), []byte(strings.TrimSpace(stage)), 0644)
+	err := os.MkdirAll(ResolveWorkingDir(cfgPath), 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(resolveStageFile(cfgPath), []byte(strings.TrimSpace(stage)), 0644)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// BranchStageMaxLength keeps the stages derived from a branch short enough
+// for the resource names they end up in.
+const BranchStageMaxLength = 24
+
+var branchStageRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// these are checked when HEAD is detached, like it is in most CI providers
+var branchEnv = []string{
+	"GITHUB_HEAD_REF",
+	"GITHUB_REF_NAME",
+	"CI_COMMIT_REF_NAME",
+	"BITBUCKET_BRANCH",
+	"BRANCH_NAME",
+}
+
+// GitBranch returns the branch checked out in the directory.
+func GitBranch(dir string) (string, error) {
+	cmd := process.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	branch := strings.TrimSpace(string(output))
+	if err == nil && branch != "" && branch != "HEAD" {
+		return branch, nil
+	}
+	for _, key := range branchEnv {
+		if value := os.Getenv(key); value != "" {
+			return value, nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("HEAD is detached and none of %v are set", strings.Join(branchEnv, ", "))
+}
+
+// ParseStageMap parses mappings from branches to stages, like
+// `main=production,develop=staging`.
+func ParseStageMap(input string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		branch, stage, ok := strings.Cut(item, "=")
+		branch = strings.TrimSpace(branch)
+		stage = strings.TrimSpace(stage)
+		if !ok || branch == "" || stage == "" {
+			return nil, fmt.Errorf("%q should look like branch=stage", item)
+		}
+		if InvalidStageRegex.MatchString(stage) {
+			return nil, fmt.Errorf("%q is not a valid stage name", stage)
+		}
+		result[branch] = stage
+	}
+	return result, nil
+}
+
+// BranchStage derives a stage from a branch. Mapped branches use the stage
+// they are mapped to, the rest are lowercased with anything that isn't a
+// letter or a number turned into a dash. Stages that are too long are cut
+// short with a hash of the branch so they stay unique.
+func BranchStage(branch string, mappings map[string]string) string {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if stage, ok := mappings[branch]; ok {
+		return stage
+	}
+	stage := strings.Trim(branchStageRegex.ReplaceAllString(strings.ToLower(branch), "-"), "-")
+	if stage != "" && len(stage) <= BranchStageMaxLength {
+		return stage
+	}
+	sum := sha256.Sum256([]byte(branch))
+	suffix := hex.EncodeToString(sum[:])[:6]
+	prefix := stage
+	if len(prefix) > BranchStageMaxLength-len(suffix)-1 {
+		prefix = strings.TrimRight(prefix[:BranchStageMaxLength-len(suffix)-1], "-")
+	}
+	if prefix == "" {
+		prefix = "branch"
+	}
+	return prefix + "-" + suffix
+}
@@ -0,0 +1,50 @@
+package project_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sst/sst/v3/pkg/project"
+)
+
+func TestBranchStage(t *testing.T) {
+	mappings, err := project.ParseStageMap("main=production, develop=staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		branch   string
+		expected string
+	}{
+		{"main", "production"},
+		{"refs/heads/develop", "staging"},
+		{"feature/Login_Page", "feature-login-page"},
+		{"--fix--", "fix"},
+		{"dependabot/npm_and_yarn/some-package-1.2.3", ""},
+		{"日本語", ""},
+	}
+	for _, test := range tests {
+		stage := project.BranchStage(test.branch, mappings)
+		if test.expected != "" && stage != test.expected {
+			t.Errorf("expected %v to be %v, got %v", test.branch, test.expected, stage)
+		}
+		if len(stage) > project.BranchStageMaxLength || project.InvalidStageRegex.MatchString(stage) || strings.HasSuffix(stage, "--") {
+			t.Errorf("invalid stage %v for %v", stage, test.branch)
+		}
+	}
+
+	a := project.BranchStage("feature/a-very-long-branch-name-one", nil)
+	b := project.BranchStage("feature/a-very-long-branch-name-two", nil)
+	if a == b {
+		t.Errorf("expected long branches to get different stages, both got %v", a)
+	}
+}
+
+func TestParseStageMap(t *testing.T) {
+	for _, input := range []string{"main", "main=", "main=prod/uction"} {
+		_, err := project.ParseStageMap(input)
+		if err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
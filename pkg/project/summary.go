@@ -0,0 +1,61 @@
+package project
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// UpdateSummary groups the resources an update run with --continue touched so
+// a partially failed deploy shows what was left behind.
+type UpdateSummary struct {
+	Succeeded []string
+	Failed    []string
+	Skipped   []string
+}
+
+// summarize builds the summary from the resources that changed and failed
+// during the update. Pulumi doesn't emit events for the steps it never gets
+// to, like the resources that depend on a failed one, so the skipped ones are
+// the steps planned before the update that neither succeeded nor failed.
+func summarize(planned []apitype.StepEventMetadata, succeeded []string, failed []string) *UpdateSummary {
+	summary := &UpdateSummary{
+		Succeeded: []string{},
+		Failed:    []string{},
+		Skipped:   []string{},
+	}
+	for _, urn := range succeeded {
+		if summarizable(urn) && !slices.Contains(failed, urn) && !slices.Contains(summary.Succeeded, urn) {
+			summary.Succeeded = append(summary.Succeeded, urn)
+		}
+	}
+	for _, urn := range failed {
+		if summarizable(urn) && !slices.Contains(summary.Failed, urn) {
+			summary.Failed = append(summary.Failed, urn)
+		}
+	}
+	if len(summary.Failed) == 0 {
+		return summary
+	}
+	for _, step := range planned {
+		urn := step.URN
+		if step.Op == apitype.OpSame || !summarizable(urn) {
+			continue
+		}
+		if slices.Contains(summary.Failed, urn) || slices.Contains(summary.Succeeded, urn) || slices.Contains(summary.Skipped, urn) {
+			continue
+		}
+		summary.Skipped = append(summary.Skipped, urn)
+	}
+	return summary
+}
+
+func summarizable(urn string) bool {
+	if urn == "" {
+		return false
+	}
+	kind := string(resource.URN(urn).Type())
+	return kind != "pulumi:pulumi:Stack" && !strings.HasPrefix(kind, "pulumi:providers:")
+}
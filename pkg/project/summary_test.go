@@ -0,0 +1,82 @@
+package project
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestSummarize(t *testing.T) {
+	const prefix = "urn:pulumi:dev::app::"
+	bucket := prefix + "aws:s3/bucketV2:BucketV2::Bucket"
+	policy := prefix + "aws:s3/bucketPolicy:BucketPolicy::Policy"
+	notification := prefix + "aws:s3/bucketNotification:BucketNotification::Notification"
+	function := prefix + "aws:lambda/function:Function::Function"
+	table := prefix + "aws:dynamodb/table:Table::Table"
+	tests := []struct {
+		name      string
+		planned   []apitype.StepEventMetadata
+		succeeded []string
+		failed    []string
+		expected  UpdateSummary
+	}{
+		{
+			name: "new stage",
+			planned: []apitype.StepEventMetadata{
+				{URN: prefix + "pulumi:pulumi:Stack::app-dev", Op: apitype.OpCreate},
+				{URN: prefix + "pulumi:providers:aws::default", Op: apitype.OpCreate},
+				{URN: bucket, Op: apitype.OpCreate},
+				{URN: policy, Op: apitype.OpCreate},
+				{URN: notification, Op: apitype.OpCreate},
+				{URN: function, Op: apitype.OpCreate},
+			},
+			succeeded: []string{function},
+			failed:    []string{bucket},
+			expected: UpdateSummary{
+				Succeeded: []string{function},
+				Failed:    []string{bucket},
+				Skipped:   []string{policy, notification},
+			},
+		},
+		{
+			name: "unchanged dependents are not skipped",
+			planned: []apitype.StepEventMetadata{
+				{URN: bucket, Op: apitype.OpUpdate},
+				{URN: policy, Op: apitype.OpSame},
+				{URN: table, Op: apitype.OpReplace},
+				{URN: table, Op: apitype.OpCreateReplacement},
+				{URN: table, Op: apitype.OpDeleteReplaced},
+			},
+			failed: []string{bucket},
+			expected: UpdateSummary{
+				Succeeded: []string{},
+				Failed:    []string{bucket},
+				Skipped:   []string{table},
+			},
+		},
+		{
+			name: "nothing failed",
+			planned: []apitype.StepEventMetadata{
+				{URN: bucket, Op: apitype.OpCreate},
+				{URN: policy, Op: apitype.OpCreate},
+			},
+			succeeded: []string{bucket},
+			expected: UpdateSummary{
+				Succeeded: []string{bucket},
+				Failed:    []string{},
+				Skipped:   []string{},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := summarize(test.planned, test.succeeded, test.failed)
+			if !slices.Equal(got.Succeeded, test.expected.Succeeded) ||
+				!slices.Equal(got.Failed, test.expected.Failed) ||
+				!slices.Equal(got.Skipped, test.expected.Skipped) {
+				t.Fatalf("expected %+v, got %+v", test.expected, *got)
+			}
+		})
+	}
+}
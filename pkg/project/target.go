@@ -0,0 +1,122 @@
+package project
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/internal/util"
+)
+
+type TargetsEvent struct {
+	Target  []string
+	Exclude []string
+	Replace []string
+}
+
+// resolveURNs maps the names of components in the config to their URNs in the
+// state. The label is used in the error when a name can't be found.
+func resolveURNs(resources []apitype.ResourceV3, label string, names []string) ([]string, error) {
+	result := []string{}
+	for _, item := range names {
+		index := slices.IndexFunc(resources, func(res apitype.ResourceV3) bool {
+			return res.URN.Name() == item
+		})
+		if index == -1 {
+			return nil, util.NewReadableError(nil, fmt.Sprintf("%v not found: %v", label, item))
+		}
+		result = append(result, string(resources[index].URN))
+	}
+	return result, nil
+}
+
+// withDependents returns the given URNs along with every resource in the state
+// that depends on them, either directly, through a property, through its
+// provider or by being a child of one of them. The state is ordered so that
+// dependencies always come first, which lets this be done in a single pass.
+func withDependents(resources []apitype.ResourceV3, urns []string) []string {
+	set := map[resource.URN]bool{}
+	for _, urn := range urns {
+		set[resource.URN(urn)] = true
+	}
+	result := slices.Clone(urns)
+	for _, res := range resources {
+		if set[res.URN] {
+			continue
+		}
+		depends := set[res.Parent] || slices.ContainsFunc(res.Dependencies, func(dep resource.URN) bool {
+			return set[dep]
+		})
+		for _, deps := range res.PropertyDependencies {
+			if depends {
+				break
+			}
+			depends = slices.ContainsFunc(deps, func(dep resource.URN) bool {
+				return set[dep]
+			})
+		}
+		// provider references are the provider's urn followed by ::id
+		if index := strings.LastIndex(res.Provider, "::"); !depends && index != -1 {
+			depends = set[resource.URN(res.Provider[:index])]
+		}
+		if !depends {
+			continue
+		}
+		set[res.URN] = true
+		result = append(result, string(res.URN))
+	}
+	return result
+}
+
+// pendingCreates returns the resources a preview would create that aren't
+// under an excluded resource. When within is set, only the ones under it are
+// returned. The steps are ordered so that parents always come first.
+func pendingCreates(steps []apitype.StepEventMetadata, within []string, excludes []string) []string {
+	excluded := map[string]bool{}
+	for _, urn := range excludes {
+		excluded[urn] = true
+	}
+	included := map[string]bool{}
+	for _, urn := range within {
+		included[urn] = true
+	}
+	result := []string{}
+	for _, step := range steps {
+		if step.Op != apitype.OpCreate || step.New == nil {
+			continue
+		}
+		if excluded[step.New.Parent] {
+			excluded[step.URN] = true
+			continue
+		}
+		if within != nil && !included[step.New.Parent] {
+			continue
+		}
+		included[step.URN] = true
+		result = append(result, step.URN)
+	}
+	return result
+}
+
+// withCustomChildren replaces components with the custom resources under them,
+// at any depth. Pulumi can only act on custom resources, so replacing a
+// component on its own does nothing.
+func withCustomChildren(resources []apitype.ResourceV3, urns []string) []string {
+	set := map[resource.URN]bool{}
+	for _, urn := range urns {
+		set[resource.URN(urn)] = true
+	}
+	result := []string{}
+	for _, res := range resources {
+		if !set[res.URN] && !set[res.Parent] {
+			continue
+		}
+		set[res.URN] = true
+		if res.Custom && !slices.Contains(result, string(res.URN)) {
+			result = append(result, string(res.URN))
+		}
+	}
+	return result
+}
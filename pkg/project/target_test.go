@@ -0,0 +1,122 @@
+package project
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+const targetPrefix = "urn:pulumi:dev::app::"
+
+var targetResources = []apitype.ResourceV3{
+	{URN: targetPrefix + "pulumi:pulumi:Stack::app-dev"},
+	{URN: targetPrefix + "pulumi:providers:aws::default", Custom: true},
+	{URN: targetPrefix + "sst:aws:Bucket::MyBucket", Parent: targetPrefix + "pulumi:pulumi:Stack::app-dev"},
+	{URN: targetPrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket", Parent: targetPrefix + "sst:aws:Bucket::MyBucket", Custom: true},
+	{URN: targetPrefix + "sst:aws:Bucket$sst:aws:Policy::MyBucketPolicy", Parent: targetPrefix + "sst:aws:Bucket::MyBucket"},
+	{URN: targetPrefix + "sst:aws:Bucket$sst:aws:Policy$aws:s3/bucketPolicy:BucketPolicy::MyBucketPolicyResource", Parent: targetPrefix + "sst:aws:Bucket$sst:aws:Policy::MyBucketPolicy", Custom: true},
+	{URN: targetPrefix + "sst:aws:Function::MyFunction", Parent: targetPrefix + "pulumi:pulumi:Stack::app-dev"},
+	{URN: targetPrefix + "sst:aws:Function$aws:lambda/function:Function::MyFunctionFunction", Parent: targetPrefix + "sst:aws:Function::MyFunction", Custom: true, Dependencies: []resource.URN{targetPrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket"}},
+}
+
+func TestReplaceComponent(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected []string
+	}{
+		{
+			name:  "component",
+			names: []string{"MyBucket"},
+			expected: []string{
+				targetPrefix + "sst:aws:Bucket$aws:s3/bucketV2:BucketV2::MyBucketBucket",
+				targetPrefix + "sst:aws:Bucket$sst:aws:Policy$aws:s3/bucketPolicy:BucketPolicy::MyBucketPolicyResource",
+			},
+		},
+		{
+			name:     "custom resource",
+			names:    []string{"MyFunctionFunction"},
+			expected: []string{targetPrefix + "sst:aws:Function$aws:lambda/function:Function::MyFunctionFunction"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urns, err := resolveURNs(targetResources, "Replace", test.names)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := withCustomChildren(targetResources, urns)
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveURNsNotFound(t *testing.T) {
+	if _, err := resolveURNs(targetResources, "Replace", []string{"Missing"}); err == nil {
+		t.Fatal("expected an error for a missing name")
+	}
+}
+
+func TestWithDependents(t *testing.T) {
+	got := withDependents(targetResources, []string{targetPrefix + "sst:aws:Bucket::MyBucket"})
+	if !slices.Contains(got, targetPrefix+"sst:aws:Function$aws:lambda/function:Function::MyFunctionFunction") {
+		t.Fatalf("expected the function that depends on the bucket, got %v", got)
+	}
+	if slices.Contains(got, targetPrefix+"sst:aws:Function::MyFunction") {
+		t.Fatalf("expected the function component to be left out, got %v", got)
+	}
+}
+
+func TestPendingCreates(t *testing.T) {
+	stack := targetPrefix + "pulumi:pulumi:Stack::app-dev"
+	bucket := targetPrefix + "sst:aws:Bucket::MyBucket"
+	function := targetPrefix + "sst:aws:Function::MyFunction"
+	create := func(urn string, parent string) apitype.StepEventMetadata {
+		return apitype.StepEventMetadata{
+			Op:  apitype.OpCreate,
+			URN: urn,
+			New: &apitype.StepEventStateMetadata{URN: urn, Parent: parent},
+		}
+	}
+	steps := []apitype.StepEventMetadata{
+		{Op: apitype.OpSame, URN: bucket, New: &apitype.StepEventStateMetadata{URN: bucket, Parent: stack}},
+		create(targetPrefix+"sst:aws:Queue::MyQueue", stack),
+		create(targetPrefix+"sst:aws:Queue$aws:sqs/queue:Queue::MyQueueQueue", targetPrefix+"sst:aws:Queue::MyQueue"),
+		create(targetPrefix+"sst:aws:Bucket$aws:s3/bucketNotification:BucketNotification::MyBucketNotification", bucket),
+		create(targetPrefix+"sst:aws:Function$aws:iam/role:Role::MyFunctionRole", function),
+	}
+	tests := []struct {
+		name     string
+		within   []string
+		excludes []string
+		expected []string
+	}{
+		{
+			name:     "everything but the excluded",
+			excludes: []string{bucket},
+			expected: []string{
+				targetPrefix + "sst:aws:Queue::MyQueue",
+				targetPrefix + "sst:aws:Queue$aws:sqs/queue:Queue::MyQueueQueue",
+				targetPrefix + "sst:aws:Function$aws:iam/role:Role::MyFunctionRole",
+			},
+		},
+		{
+			name:     "within a target",
+			within:   []string{function},
+			excludes: []string{bucket},
+			expected: []string{targetPrefix + "sst:aws:Function$aws:iam/role:Role::MyFunctionRole"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := pendingCreates(steps, test.within, test.excludes)
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
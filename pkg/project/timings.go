@@ -0,0 +1,189 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+type ResourceTiming struct {
+	URN      string         `json:"urn"`
+	Type     string         `json:"type"`
+	Op       apitype.OpType `json:"op"`
+	Start    time.Time      `json:"start"`
+	Duration time.Duration  `json:"-"`
+	Critical bool           `json:"critical"`
+}
+
+func (t ResourceTiming) MarshalJSON() ([]byte, error) {
+	type alias ResourceTiming
+	return json.Marshal(struct {
+		alias
+		DurationMs int64 `json:"durationMs"`
+	}{alias(t), t.Duration.Milliseconds()})
+}
+
+// TimingsEvent is published at the end of an update with how long each
+// resource took, slowest first, and the chain of dependencies that took the
+// longest end to end.
+type TimingsEvent struct {
+	Resources    []ResourceTiming `json:"resources"`
+	CriticalPath []string         `json:"criticalPath"`
+	Total        time.Duration    `json:"-"`
+}
+
+func (evt TimingsEvent) MarshalJSON() ([]byte, error) {
+	type alias TimingsEvent
+	return json.Marshal(struct {
+		alias
+		TotalMs int64 `json:"totalMs"`
+	}{alias(evt), evt.Total.Milliseconds()})
+}
+
+func (evt *TimingsEvent) Write(path string) error {
+	data, err := json.MarshalIndent(evt, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+type timer struct {
+	started  map[string]time.Time
+	timings  map[string]*ResourceTiming
+	first    time.Time
+	finished time.Time
+}
+
+func newTimer() *timer {
+	return &timer{
+		started: map[string]time.Time{},
+		timings: map[string]*ResourceTiming{},
+	}
+}
+
+// start and finish are called as the events are read from the event log. The
+// timestamps in the events only have second precision so the time they're
+// read at is used instead, like the progress output does.
+func (t *timer) start(metadata apitype.StepEventMetadata, now time.Time) {
+	if t.first.IsZero() {
+		t.first = now
+	}
+	t.started[metadata.URN] = now
+}
+
+func (t *timer) finish(metadata apitype.StepEventMetadata, now time.Time) {
+	t.finished = now
+	started, ok := t.started[metadata.URN]
+	if !ok || metadata.Op == apitype.OpSame || !summarizable(metadata.URN) {
+		return
+	}
+	custom := (metadata.New != nil && metadata.New.Custom) || (metadata.Old != nil && metadata.Old.Custom)
+	if !custom {
+		// components last as long as all their children
+		return
+	}
+	t.timings[metadata.URN] = &ResourceTiming{
+		URN:      metadata.URN,
+		Type:     metadata.Type,
+		Op:       metadata.Op,
+		Start:    started,
+		Duration: now.Sub(started),
+	}
+}
+
+// event sorts the timings and works out the critical path through the
+// dependency graph of the given resources. When removing, resources are
+// deleted before their dependencies so the graph is walked in reverse.
+func (t *timer) event(resources []apitype.ResourceV3, reverse bool) *TimingsEvent {
+	result := &TimingsEvent{
+		Resources:    []ResourceTiming{},
+		CriticalPath: []string{},
+		Total:        t.finished.Sub(t.first),
+	}
+	path := criticalPath(resources, t.timings, reverse)
+	for _, urn := range path {
+		t.timings[urn].Critical = true
+	}
+	result.CriticalPath = path
+	for _, timing := range t.timings {
+		result.Resources = append(result.Resources, *timing)
+	}
+	sort.Slice(result.Resources, func(i, j int) bool {
+		return result.Resources[i].Duration > result.Resources[j].Duration
+	})
+	return result
+}
+
+func criticalPath(resources []apitype.ResourceV3, timings map[string]*ResourceTiming, reverse bool) []string {
+	order := []string{}
+	edges := map[string][]string{}
+	for _, res := range resources {
+		urn := string(res.URN)
+		order = append(order, urn)
+		deps := []string{}
+		for _, dep := range res.Dependencies {
+			deps = append(deps, string(dep))
+		}
+		for _, items := range res.PropertyDependencies {
+			for _, dep := range items {
+				deps = append(deps, string(dep))
+			}
+		}
+		// provider references are the provider's urn followed by ::id
+		if index := strings.LastIndex(res.Provider, "::"); index != -1 {
+			deps = append(deps, res.Provider[:index])
+		}
+		for _, dep := range deps {
+			if reverse {
+				edges[dep] = append(edges[dep], urn)
+				continue
+			}
+			edges[urn] = append(edges[urn], dep)
+		}
+	}
+	if reverse {
+		slices.Reverse(order)
+	}
+
+	duration := func(urn string) time.Duration {
+		if timing, ok := timings[urn]; ok {
+			return timing.Duration
+		}
+		return 0
+	}
+	total := map[string]time.Duration{}
+	previous := map[string]string{}
+	end := ""
+	for _, urn := range order {
+		for _, dep := range edges[urn] {
+			if _, ok := total[dep]; ok && total[dep] > total[urn] {
+				total[urn] = total[dep]
+				previous[urn] = dep
+			}
+		}
+		total[urn] += duration(urn)
+		if end == "" || total[urn] > total[end] {
+			end = urn
+		}
+	}
+
+	result := []string{}
+	for urn := end; urn != ""; urn = previous[urn] {
+		if duration(urn) > 0 {
+			result = append(result, urn)
+		}
+	}
+	slices.Reverse(result)
+	return result
+}
@@ -73,6 +73,12 @@ func (w *PulumiWorkdir) Push(updateID string) error {
 	app := w.project.app.Name
 	home := w.project.Backend()
 
+	// the state no longer matches the inputs of the last deploy
+	err = provider.RemoveInputHash(home, app, stage)
+	if err != nil {
+		return err
+	}
+
 	var group errgroup.Group
 	group.Go(func() error {
 		return w.pushPartial(updateID, data)
@@ -121,11 +127,16 @@ func (w *PulumiWorkdir) state() string {
 }
 
 func (w *PulumiWorkdir) Export() (*apitype.CheckpointV3, error) {
+	return readCheckpoint(w.state())
+}
+
+func readCheckpoint(path string) (*apitype.CheckpointV3, error) {
 	var untyped apitype.VersionedCheckpoint
-	file, err := os.Open(w.state())
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 	err = json.NewDecoder(file).Decode(&untyped)
 	if err != nil {
 		return nil, err
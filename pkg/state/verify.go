@@ -0,0 +1,132 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+type IssueCode string
+
+const (
+	IssueMissingParent             IssueCode = "missing-parent"
+	IssueMissingDependency         IssueCode = "missing-dependency"
+	IssueMissingPropertyDependency IssueCode = "missing-property-dependency"
+	IssueMissingProvider           IssueCode = "missing-provider"
+	IssueInvalidProvider           IssueCode = "invalid-provider"
+	IssueDuplicateURN              IssueCode = "duplicate-urn"
+	IssuePendingOperation          IssueCode = "pending-operation"
+	IssueDecryptFailed             IssueCode = "decrypt-failed"
+)
+
+// Issue is a problem with the state. Fix is what `sst state repair` would do
+// about it, or empty if it's not something repair can fix.
+type Issue struct {
+	Code     IssueCode    `json:"code"`
+	Resource resource.URN `json:"resource,omitempty"`
+	Message  string       `json:"message"`
+	Fix      string       `json:"fix,omitempty"`
+}
+
+// Verify checks the references between the resources in the checkpoint
+// without changing it. It goes through the resources the same way Repair
+// does, so a resource whose parent would be removed is reported as well.
+func Verify(checkpoint *apitype.CheckpointV3) []Issue {
+	result := []Issue{}
+	if checkpoint.Latest == nil {
+		return result
+	}
+	resources := map[resource.URN]bool{}
+	for _, item := range checkpoint.Latest.Resources {
+		resources[item.URN] = true
+	}
+	seen := map[resource.URN]bool{}
+	for _, item := range checkpoint.Latest.Resources {
+		if seen[item.URN] {
+			result = append(result, Issue{
+				Code:     IssueDuplicateURN,
+				Resource: item.URN,
+				Message:  "The resource is listed more than once",
+			})
+		}
+		seen[item.URN] = true
+	}
+	for _, item := range checkpoint.Latest.Resources {
+		if item.Parent != "" {
+			if _, ok := resources[item.Parent]; !ok {
+				result = append(result, Issue{
+					Code:     IssueMissingParent,
+					Resource: item.URN,
+					Message:  fmt.Sprintf("The parent %s is not in the state", item.Parent),
+					Fix:      "Remove the resource",
+				})
+				delete(resources, item.URN)
+				continue
+			}
+		}
+		for _, dependency := range item.Dependencies {
+			if _, ok := resources[dependency]; !ok {
+				result = append(result, Issue{
+					Code:     IssueMissingDependency,
+					Resource: item.URN,
+					Message:  fmt.Sprintf("The dependency %s is not in the state", dependency),
+					Fix:      "Remove the dependency",
+				})
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(item.PropertyDependencies)) {
+			for _, dependency := range item.PropertyDependencies[key] {
+				if _, ok := resources[dependency]; !ok {
+					result = append(result, Issue{
+						Code:     IssueMissingPropertyDependency,
+						Resource: item.URN,
+						Message:  fmt.Sprintf("The dependency %s of the property %s is not in the state", dependency, key),
+						Fix:      "Remove the dependency from the property",
+					})
+				}
+			}
+		}
+		if item.Provider != "" {
+			ref, err := providers.ParseReference(item.Provider)
+			if err != nil {
+				result = append(result, Issue{
+					Code:     IssueInvalidProvider,
+					Resource: item.URN,
+					Message:  fmt.Sprintf("The provider reference %s is invalid", item.Provider),
+				})
+			} else if _, ok := resources[ref.URN()]; !ok {
+				result = append(result, Issue{
+					Code:     IssueMissingProvider,
+					Resource: item.URN,
+					Message:  fmt.Sprintf("The provider %s is not in the state", ref.URN()),
+				})
+			}
+		}
+	}
+	for _, operation := range checkpoint.Latest.PendingOperations {
+		result = append(result, Issue{
+			Code:     IssuePendingOperation,
+			Resource: operation.Resource.URN,
+			Message:  fmt.Sprintf("A %s operation did not complete, the resource may or may not exist", operation.Type),
+		})
+	}
+	return result
+}
+
+// VerifyDecrypt checks that the checkpoint decrypts with the passphrase.
+func VerifyDecrypt(ctx context.Context, passphrase string, checkpoint *apitype.CheckpointV3) *Issue {
+	copy := *checkpoint
+	_, err := Decrypt(ctx, passphrase, &copy)
+	if err == nil {
+		return nil
+	}
+	return &Issue{
+		Code:    IssueDecryptFailed,
+		Message: fmt.Sprintf("The state could not be decrypted with the passphrase of the stage: %v", err),
+	}
+}
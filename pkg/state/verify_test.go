@@ -0,0 +1,133 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/sst/sst/v3/pkg/state"
+)
+
+const (
+	stackURN    resource.URN = "urn:pulumi:dev::app::pulumi:pulumi:Stack::app-dev"
+	providerURN resource.URN = "urn:pulumi:dev::app::pulumi:providers:aws::default"
+	bucketURN   resource.URN = "urn:pulumi:dev::app::aws:s3/bucketV2:BucketV2::Bucket"
+	policyURN   resource.URN = "urn:pulumi:dev::app::aws:s3/bucketPolicy:BucketPolicy::Policy"
+	missingURN  resource.URN = "urn:pulumi:dev::app::sst:aws:Bucket::Missing"
+)
+
+func checkpoint(resources ...apitype.ResourceV3) *apitype.CheckpointV3 {
+	return &apitype.CheckpointV3{
+		Latest: &apitype.DeploymentV3{
+			Resources: resources,
+		},
+	}
+}
+
+func codes(issues []state.Issue) []state.IssueCode {
+	result := []state.IssueCode{}
+	for _, issue := range issues {
+		result = append(result, issue.Code)
+	}
+	return result
+}
+
+func TestVerify(t *testing.T) {
+	stack := apitype.ResourceV3{URN: stackURN}
+	provider := apitype.ResourceV3{URN: providerURN, ID: "provider-id", Parent: stackURN}
+	bucket := apitype.ResourceV3{URN: bucketURN, Parent: stackURN, Provider: string(providerURN) + "::provider-id"}
+	tests := []struct {
+		name       string
+		checkpoint *apitype.CheckpointV3
+		expected   []state.IssueCode
+	}{
+		{
+			name:       "valid",
+			checkpoint: checkpoint(stack, provider, bucket),
+			expected:   []state.IssueCode{},
+		},
+		{
+			name: "missing parent removes children",
+			checkpoint: checkpoint(
+				stack,
+				provider,
+				apitype.ResourceV3{URN: bucketURN, Parent: missingURN},
+				apitype.ResourceV3{URN: policyURN, Parent: bucketURN},
+			),
+			expected: []state.IssueCode{state.IssueMissingParent, state.IssueMissingParent},
+		},
+		{
+			name: "missing dependencies",
+			checkpoint: checkpoint(
+				stack,
+				apitype.ResourceV3{
+					URN:                  policyURN,
+					Parent:               stackURN,
+					Dependencies:         []resource.URN{missingURN},
+					PropertyDependencies: map[resource.PropertyKey][]resource.URN{"bucket": {missingURN}},
+				},
+			),
+			expected: []state.IssueCode{state.IssueMissingDependency, state.IssueMissingPropertyDependency},
+		},
+		{
+			name:       "missing provider",
+			checkpoint: checkpoint(stack, bucket),
+			expected:   []state.IssueCode{state.IssueMissingProvider},
+		},
+		{
+			name: "invalid provider",
+			checkpoint: checkpoint(
+				stack,
+				apitype.ResourceV3{URN: bucketURN, Parent: stackURN, Provider: "default"},
+			),
+			expected: []state.IssueCode{state.IssueInvalidProvider},
+		},
+		{
+			name:       "duplicate",
+			checkpoint: checkpoint(stack, provider, bucket, bucket),
+			expected:   []state.IssueCode{state.IssueDuplicateURN},
+		},
+		{
+			name: "pending operation",
+			checkpoint: &apitype.CheckpointV3{
+				Latest: &apitype.DeploymentV3{
+					Resources: []apitype.ResourceV3{stack},
+					PendingOperations: []apitype.OperationV2{
+						{Resource: bucket, Type: apitype.OperationTypeCreating},
+					},
+				},
+			},
+			expected: []state.IssueCode{state.IssuePendingOperation},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues := state.Verify(test.checkpoint)
+			got := codes(issues)
+			if len(got) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, got)
+			}
+			for i := range got {
+				if got[i] != test.expected[i] {
+					t.Fatalf("expected %v, got %v", test.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyMatchesRepair(t *testing.T) {
+	input := checkpoint(
+		apitype.ResourceV3{URN: stackURN},
+		apitype.ResourceV3{URN: bucketURN, Parent: missingURN},
+		apitype.ResourceV3{URN: policyURN, Parent: stackURN, Dependencies: []resource.URN{bucketURN}},
+	)
+	issues := state.Verify(input)
+	mutations := state.Repair(input)
+	if len(issues) != len(mutations) {
+		t.Fatalf("expected %d issues, got %d", len(mutations), len(issues))
+	}
+	if len(state.Verify(input)) != 0 {
+		t.Fatal("expected no issues after repair")
+	}
+}
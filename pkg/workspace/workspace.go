@@ -0,0 +1,248 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sst/sst/v3/internal/fs"
+	"github.com/sst/sst/v3/internal/util"
+)
+
+const FILE = "sst.workspace.json"
+const CONFIG = "sst.config.ts"
+
+// App is an SST app in the workspace. Path is relative to the workspace and
+// can point to the config or the directory it's in. Apps are named after
+// their directory unless a name is given.
+type App struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	DependsOn []string `json:"dependsOn"`
+	Config    string   `json:"-"`
+}
+
+type Workspace struct {
+	Root        string `json:"-"`
+	Apps        []*App `json:"apps"`
+	Concurrency int    `json:"concurrency"`
+}
+
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusSkipped   Status = "skipped"
+)
+
+type Result struct {
+	App      string
+	Status   Status
+	Duration time.Duration
+	Error    error
+}
+
+// Load reads the workspace file in the directory or any of its parents. If
+// there is none, every config under the directory is part of the workspace
+// and none of them depend on each other.
+func Load(dir string) (*Workspace, error) {
+	path, err := fs.FindUp(dir, FILE)
+	if err != nil {
+		return Discover(dir)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	workspace := &Workspace{}
+	err = json.Unmarshal(data, workspace)
+	if err != nil {
+		return nil, util.NewReadableError(err, "Could not parse "+path)
+	}
+	workspace.Root = filepath.Dir(path)
+	for _, app := range workspace.Apps {
+		config := filepath.Join(workspace.Root, app.Path)
+		if !strings.HasSuffix(config, ".ts") {
+			config = filepath.Join(config, CONFIG)
+		}
+		if !fs.Exists(config) {
+			return nil, util.NewReadableError(nil, fmt.Sprintf("Could not find %v for %v in %v", CONFIG, app.Path, FILE))
+		}
+		app.Config = config
+		if app.Name == "" {
+			app.Name = workspace.name(config)
+		}
+	}
+	err = workspace.validate()
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+func Discover(dir string) (*Workspace, error) {
+	workspace := &Workspace{
+		Root: dir,
+		Apps: []*App{},
+	}
+	for _, config := range fs.FindDown(dir, CONFIG) {
+		workspace.Apps = append(workspace.Apps, &App{
+			Name:      workspace.name(config),
+			Path:      filepath.Dir(config),
+			DependsOn: []string{},
+			Config:    config,
+		})
+	}
+	return workspace, nil
+}
+
+func (w *Workspace) name(config string) string {
+	rel, err := filepath.Rel(w.Root, filepath.Dir(config))
+	if err != nil || rel == "." {
+		return filepath.Base(filepath.Dir(config))
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (w *Workspace) validate() error {
+	names := map[string]bool{}
+	for _, app := range w.Apps {
+		if names[app.Name] {
+			return util.NewReadableError(nil, fmt.Sprintf("There is more than one app named %v in %v", app.Name, FILE))
+		}
+		names[app.Name] = true
+	}
+	for _, app := range w.Apps {
+		for _, dep := range app.DependsOn {
+			if !names[dep] {
+				return util.NewReadableError(nil, fmt.Sprintf("%v depends on %v, which is not in %v", app.Name, dep, FILE))
+			}
+		}
+	}
+	_, err := w.Order()
+	return err
+}
+
+// Order sorts the apps so every app comes after the ones it depends on,
+// otherwise keeping the order they are listed in.
+func (w *Workspace) Order() ([]*App, error) {
+	result := []*App{}
+	state := map[string]int{}
+	byName := map[string]*App{}
+	for _, app := range w.Apps {
+		byName[app.Name] = app
+	}
+	var visit func(app *App, path []string) error
+	visit = func(app *App, path []string) error {
+		switch state[app.Name] {
+		case 1:
+			return util.NewReadableError(nil, "The apps in "+FILE+" depend on each other: "+strings.Join(append(path, app.Name), " -> "))
+		case 2:
+			return nil
+		}
+		state[app.Name] = 1
+		for _, dep := range app.DependsOn {
+			err := visit(byName[dep], append(path, app.Name))
+			if err != nil {
+				return err
+			}
+		}
+		state[app.Name] = 2
+		result = append(result, app)
+		return nil
+	}
+	for _, app := range w.Apps {
+		err := visit(app, []string{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Run calls fn for every app once the apps it depends on are done, with at
+// most concurrency running at a time. With reverse, apps wait for the ones
+// that depend on them instead, which is the order they need to be removed
+// in. An app is skipped if any app it waits on did not succeed.
+func (w *Workspace) Run(ctx context.Context, concurrency int, reverse bool, fn func(ctx context.Context, app *App) error) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	waits := map[string][]string{}
+	for _, app := range w.Apps {
+		if !reverse {
+			waits[app.Name] = append(waits[app.Name], app.DependsOn...)
+			continue
+		}
+		for _, dep := range app.DependsOn {
+			waits[dep] = append(waits[dep], app.Name)
+		}
+	}
+
+	done := map[string]chan struct{}{}
+	for _, app := range w.Apps {
+		done[app.Name] = make(chan struct{})
+	}
+	results := map[string]Result{}
+	var lock sync.Mutex
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, app := range w.Apps {
+		wg.Add(1)
+		go func(app *App) {
+			defer wg.Done()
+			defer close(done[app.Name])
+			result := Result{
+				App:    app.Name,
+				Status: StatusSkipped,
+			}
+			defer func() {
+				lock.Lock()
+				results[app.Name] = result
+				lock.Unlock()
+			}()
+			for _, name := range waits[app.Name] {
+				<-done[name]
+			}
+			lock.Lock()
+			blocked := slices.ContainsFunc(waits[app.Name], func(name string) bool {
+				return results[name].Status != StatusSucceeded
+			})
+			lock.Unlock()
+			if blocked {
+				return
+			}
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+			if ctx.Err() != nil {
+				return
+			}
+			start := time.Now()
+			err := fn(ctx, app)
+			result.Duration = time.Since(start)
+			result.Status = StatusSucceeded
+			if err != nil {
+				result.Status = StatusFailed
+				result.Error = err
+			}
+		}(app)
+	}
+	wg.Wait()
+
+	ordered := []Result{}
+	for _, app := range w.Apps {
+		ordered = append(ordered, results[app.Name])
+	}
+	return ordered
+}
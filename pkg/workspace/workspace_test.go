@@ -0,0 +1,129 @@
+package workspace_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sst/sst/v3/pkg/workspace"
+)
+
+func setup(t *testing.T, file string, apps ...string) string {
+	root := t.TempDir()
+	for _, app := range apps {
+		err := os.MkdirAll(filepath.Join(root, app), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(root, app, workspace.CONFIG), []byte{}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if file != "" {
+		err := os.WriteFile(filepath.Join(root, workspace.FILE), []byte(file), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	root := setup(t, `{
+		"apps": [
+			{ "path": "apps/web", "dependsOn": ["api"] },
+			{ "name": "api", "path": "apps/api/sst.config.ts", "dependsOn": ["shared"] },
+			{ "path": "shared" }
+		]
+	}`, "apps/web", "apps/api", "shared")
+	ws, err := workspace.Load(filepath.Join(root, "apps"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := ws.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, app := range order {
+		names = append(names, app.Name)
+	}
+	if len(names) != 3 || names[0] != "shared" || names[1] != "api" || names[2] != "apps/web" {
+		t.Errorf("unexpected order %v", names)
+	}
+}
+
+func TestLoadCycle(t *testing.T) {
+	root := setup(t, `{
+		"apps": [
+			{ "path": "a", "dependsOn": ["b"] },
+			{ "path": "b", "dependsOn": ["a"] }
+		]
+	}`, "a", "b")
+	_, err := workspace.Load(root)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := setup(t, "", "apps/web", "apps/api", "node_modules/pkg")
+	ws, err := workspace.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws.Apps) != 2 {
+		t.Fatalf("expected 2 apps, got %v", len(ws.Apps))
+	}
+}
+
+func TestRun(t *testing.T) {
+	ws := &workspace.Workspace{
+		Apps: []*workspace.App{
+			{Name: "shared"},
+			{Name: "api", DependsOn: []string{"shared"}},
+			{Name: "web", DependsOn: []string{"api"}},
+			{Name: "docs"},
+		},
+	}
+
+	var lock sync.Mutex
+	ran := []string{}
+	run := func(failing string) func(ctx context.Context, app *workspace.App) error {
+		return func(ctx context.Context, app *workspace.App) error {
+			lock.Lock()
+			ran = append(ran, app.Name)
+			lock.Unlock()
+			if app.Name == failing {
+				return errors.New("failed")
+			}
+			return nil
+		}
+	}
+
+	results := ws.Run(context.Background(), 1, false, run("api"))
+	expected := []workspace.Status{workspace.StatusSucceeded, workspace.StatusFailed, workspace.StatusSkipped, workspace.StatusSucceeded}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("expected %v to be %v, got %v", result.App, expected[i], result.Status)
+		}
+	}
+
+	ran = []string{}
+	ws.Run(context.Background(), 1, true, run(""))
+	index := func(name string) int {
+		for i, item := range ran {
+			if item == name {
+				return i
+			}
+		}
+		return -1
+	}
+	if !(index("web") < index("api") && index("api") < index("shared")) {
+		t.Errorf("expected dependents to run first, got %v", ran)
+	}
+}
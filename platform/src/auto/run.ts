@@ -12,6 +12,7 @@ export async function run(program: automation.PulumiFn) {
   process.chdir($cli.paths.root);
 
   addTransformationToRetainResourcesOnDelete();
+  addTransformationToImportResources();
   addTransformationToAddTags();
   addTransformationToCheckBucketsHaveMultiplePolicies();
 
@@ -42,6 +43,20 @@ function addTransformationToRetainResourcesOnDelete() {
   });
 }
 
+function addTransformationToImportResources() {
+  const imports = $cli.imports ?? [];
+  if (!imports.length) return;
+  runtime.registerStackTransformation((args: ResourceTransformationArgs) => {
+    const entry = imports.find(
+      (item) => item.name === args.name && item.type === args.type,
+    );
+    if (!entry) return undefined;
+    if ("import" in args.opts && args.opts.import) return undefined;
+    args.opts = { ...args.opts, import: entry.id } as typeof args.opts;
+    return args;
+  });
+}
+
 function addTransformationToAddTags() {
   runtime.registerStackTransformation((args: ResourceTransformationArgs) => {
     if ("import" in args.opts && args.opts.import) {
@@ -1741,6 +1741,7 @@ export class Function extends Component implements Link.Linkable {
         handler: args.handler,
         internal: args._skipMetadata,
         dev: dev,
+        links,
       },
       _hint: args._skipHint ? undefined : urlEndpoint,
     });
@@ -2,6 +2,7 @@ export * as aws from "./aws/index.js";
 export * as cloudflare from "./cloudflare/index.js";
 export * as vercel from "./vercel/index.js";
 export * from "./secret.js";
+export * from "./reference.js";
 export * from "./linkable.js";
 /**
  * experimental packages, you may be fired for using
@@ -0,0 +1,140 @@
+import { VisibleError } from "./error";
+import { Output, output, secret } from "@pulumi/pulumi";
+import { Link } from "./link";
+import { Component } from "./component";
+
+// Marks the outputs that are secret in the referenced stage.
+const SECRET_KEY = "__sstSecret";
+
+function parseOutput(value: any): { value: any; secret: boolean } {
+  if (Array.isArray(value)) {
+    const items = value.map(parseOutput);
+    return {
+      value: items.map((item) => item.value),
+      secret: items.some((item) => item.secret),
+    };
+  }
+  if (value && typeof value === "object") {
+    const keys = Object.keys(value);
+    if (keys.length === 1 && keys[0] === SECRET_KEY)
+      return { value: value[SECRET_KEY], secret: true };
+    const entries = keys.map((key) => [key, parseOutput(value[key])] as const);
+    return {
+      value: Object.fromEntries(entries.map(([key, item]) => [key, item.value])),
+      secret: entries.some(([, item]) => item.secret),
+    };
+  }
+  return { value, secret: false };
+}
+
+export class ReferenceMissingError extends VisibleError {
+  constructor(public readonly referenceName: string) {
+    super(
+      `Add ${referenceName} to the \`references\` in your app config to use it`,
+    );
+  }
+}
+
+/**
+ * The `Reference` component lets you use the outputs of another app, or another stage of
+ * this app, that uses the same `home`.
+ *
+ * @example
+ *
+ * #### Declare the reference
+ *
+ * References are declared in your app config. The name of the reference is used for the
+ * component.
+ *
+ * ```ts title="sst.config.ts" {5-7}
+ * app(input) {
+ *   return {
+ *     name: "frontend",
+ *     home: "aws",
+ *     references: {
+ *       Backend: { app: "backend", stage: "production" }
+ *     }
+ *   };
+ * }
+ * ```
+ *
+ * When you deploy, the outputs are read from the state of the referenced stage. Outputs
+ * that are secret there stay secret in this app.
+ *
+ * #### Use the outputs
+ *
+ * ```ts title="sst.config.ts"
+ * const backend = new sst.Reference("Backend");
+ *
+ * new sst.aws.Nextjs("MyWeb", {
+ *   environment: {
+ *     API_URL: backend.get("api")
+ *   }
+ * });
+ * ```
+ *
+ * #### Link the reference to a resource
+ *
+ * You can also link the outputs to other resources.
+ *
+ * ```ts title="sst.config.ts"
+ * new sst.aws.Nextjs("MyWeb", {
+ *   link: [backend]
+ * });
+ * ```
+ *
+ * Once linked, you can use them in your function code.
+ *
+ * ```ts title="app/page.tsx"
+ * import { Resource } from "sst";
+ *
+ * console.log(Resource.Backend.api);
+ * ```
+ */
+export class Reference extends Component implements Link.Linkable {
+  private _name: string;
+  private _outputs: Record<string, Output<any>>;
+
+  constructor(name: string) {
+    super("sst:sst:Reference", name, {}, {});
+    this._name = name;
+    const value = process.env["SST_REFERENCE_" + name];
+    if (value === undefined) throw new ReferenceMissingError(name);
+    this._outputs = Object.fromEntries(
+      Object.entries(JSON.parse(value)).map(([key, item]) => {
+        const parsed = parseOutput(item);
+        return [key, parsed.secret ? secret(parsed.value) : output(parsed.value)];
+      }),
+    );
+  }
+
+  /**
+   * The name of the reference.
+   */
+  public get name() {
+    return output(this._name);
+  }
+
+  /**
+   * All the outputs of the referenced stage.
+   */
+  public get outputs(): Output<Record<string, any>> {
+    return output(this._outputs);
+  }
+
+  /**
+   * Get a single output of the referenced stage.
+   *
+   * @param key The name of the output.
+   */
+  public get(key: string): Output<any> {
+    return output(this._outputs[key]);
+  }
+
+  /** @internal */
+  public getSSTLink() {
+    return {
+      properties: this._outputs,
+    };
+  }
+}
@@ -215,6 +215,28 @@ export interface App {
    * }
    * ```
    *
+   * To make sure a stage is only ever deployed to the right account, set what it `expect`s.
+   * For AWS this can be the `account` and `region`, and for Cloudflare the `account`. These
+   * are checked before anything else happens, including in `sst dev` and `sst shell`.
+   *
+   * ```ts
+   * {
+   *   providers: {
+   *     aws: {
+   *       expect: input.stage === "production"
+   *         ? { account: "123456789012", region: "us-east-1" }
+   *         : undefined
+   *     },
+   *     cloudflare: {
+   *       expect: input.stage === "production" ? { account: "3b2f..." } : undefined
+   *     }
+   *   }
+   * }
+   * ```
+   *
+   * If the credentials are for a different account, the command stops. Each of these can
+   * also be a list.
+   *
    * @default The `home` provider.
    */
   providers?: Record<string, any>;
@@ -271,6 +293,69 @@ export interface App {
    */
   protect?: boolean;
 
+  /**
+   * The names of the components in your app that should never be deleted or replaced.
+   *
+   * While `protect` stops `sst remove` from removing a stage, a change to your config can
+   * still delete or replace a resource when you run `sst deploy` or `sst dev`. For
+   * something like a database, this means losing its data.
+   *
+   * ```ts
+   * {
+   *   protected: input.stage === "production" ? ["Database", "Uploads"] : []
+   * }
+   * ```
+   *
+   * The changes are previewed before they are deployed. If a protected component, or any of
+   * the resources inside it, would be deleted or replaced, the deploy is stopped. The same
+   * applies to `sst remove`.
+   *
+   * If you really want to delete or replace it, pass in its name to `--allow-destroy`.
+   *
+   * ```bash
+   * sst deploy --stage production --allow-destroy Database
+   * ```
+   */
+  protected?: string[];
+
+  /**
+   * Windows where the stage can't be deployed, removed or refreshed. A window is either a
+   * date range, or it starts on a cron schedule and lasts for a duration.
+   *
+   * @example
+   *
+   * Stop production deploys over the holidays and on weekends.
+   *
+   * ```ts
+   * {
+   *   freeze: input.stage === "production" ? [
+   *     { start: "2026-12-20", end: "2027-01-02", reason: "Holidays" },
+   *     { cron: "0 18 * * 5", duration: "62h", timezone: "America/New_York" }
+   *   ] : []
+   * }
+   * ```
+   *
+   * Days can also be times like `2026-12-20T18:00:00Z`, and the end day is included. Days
+   * and the cron are in UTC unless a `timezone` is given.
+   *
+   * If a deploy can't wait, pass in the reason to `--override-freeze`. The reason is recorded
+   * with the update.
+   *
+   * ```bash
+   * sst deploy --stage production --override-freeze "Fix checkout outage"
+   * ```
+   *
+   * Freeze windows can also be added to a stage with `sst stage freeze`.
+   */
+  freeze?: {
+    start?: string;
+    end?: string;
+    cron?: string;
+    duration?: string;
+    timezone?: string;
+    reason?: string;
+  }[];
+
   /**
    * Configure which directories should be watched for changes when running `sst dev`.
    * By default, all directories are watched (except node_modules and hidden directories).
@@ -286,6 +371,39 @@ export interface App {
    * The paths are relative to the project root.
    */
   watch?: string[];
+
+  /**
+   * Reference the outputs of other apps, or other stages of this app, that use the same
+   * `home`. The `stage` defaults to the current stage.
+   *
+   * @example
+   * ```ts
+   * {
+   *   references: {
+   *     Backend: { app: "backend", stage: "production" }
+   *   }
+   * }
+   * ```
+   *
+   * The outputs are read from the state when you deploy. You can then use them in your
+   * `run` function with the [`Reference`](/docs/component/reference) component, and link it
+   * to your resources.
+   *
+   * ```ts title="sst.config.ts"
+   * const backend = new sst.Reference("Backend");
+   *
+   * new sst.aws.Nextjs("MyWeb", {
+   *   link: [backend],
+   *   environment: {
+   *     API_URL: backend.get("api")
+   *   }
+   * });
+   * ```
+   *
+   * The deploy fails if the referenced stage has never been deployed, or if it's being
+   * updated at the same time.
+   */
+  references?: Record<string, { app: string; stage?: string }>;
 }
 
 export interface AppInput {
@@ -398,5 +398,10 @@ declare global {
     state: {
       version: Record<string, number>;
     };
+    imports?: {
+      name: string;
+      type: string;
+      id: string;
+    }[];
   };
 }
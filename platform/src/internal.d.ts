@@ -12,6 +12,11 @@ declare global {
     state: {
       version: Record<string, number>;
     };
+    imports?: {
+      name: string;
+      type: string;
+      id: string;
+    }[];
   };
 }
 
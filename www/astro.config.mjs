@@ -248,6 +248,7 @@ const sidebar = [
       "docs/reference/global",
       "docs/reference/config",
       "docs/component/secret",
+      "docs/component/reference",
       "docs/component/linkable",
       "docs/component/experimental/dev-command",
     ],
@@ -346,6 +346,25 @@ function generateCommonErrorsDoc() {
       "",
       "The error messages and descriptions in this doc are auto-generated from the CLI.",
       "",
+      "You can add your own rules for errors that are specific to your app in a `sst.errors.json`",
+      "file next to your `sst.config.ts`. Each rule has a `code`, a `match` on the error message",
+      "and an optional `type` match on the type of the resource that failed. These take either a",
+      "`contains` substring or a `regex`. The `short` lines, `docs` link, and `command` are",
+      "printed below the error.",
+      "",
+      "```json title=\"sst.errors.json\"",
+      "[",
+      "  {",
+      "    \"code\": \"LegacyVpc\",",
+      "    \"match\": { \"contains\": \"InvalidSubnetID.NotFound\" },",
+      "    \"type\": { \"regex\": \"^aws:ec2\" },",
+      "    \"short\": [\"The legacy VPC was removed, import the new one.\"],",
+      "    \"docs\": \"https://wiki.example.com/vpc\",",
+      "    \"command\": \"sst refresh --target Vpc\"",
+      "  }",
+      "]",
+      "```",
+      "",
     ];
   }
 
@@ -2150,6 +2169,7 @@ async function buildComponents() {
       "../platform/src/components/experimental/dev-command.ts",
       "../platform/src/components/linkable.ts",
       "../platform/src/components/secret.ts",
+      "../platform/src/components/reference.ts",
       "../platform/src/components/aws/analog.ts",
       "../platform/src/components/aws/apigateway-websocket.ts",
       "../platform/src/components/aws/apigateway-websocket-route.ts",